@@ -0,0 +1,536 @@
+package healthcrm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	secondsPerDay  = 24 * 60 * 60
+	secondsPerWeek = 7 * secondsPerDay
+)
+
+// TimeOfDay is a time of day as the number of seconds since midnight.
+// EndOfDay (24:00) is used for periods that run until midnight.
+type TimeOfDay int
+
+// EndOfDay is midnight at the end of the day
+const EndOfDay TimeOfDay = secondsPerDay
+
+// ParseTimeOfDay reads a time of day given as HH:MM or HH:MM:SS e.g 08:00 or 17:30:00
+func ParseTimeOfDay(value string) (TimeOfDay, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 2 && len(parts) != 3 {
+		return 0, fmt.Errorf("invalid time of day %q: expected HH:MM", value)
+	}
+
+	var fields [3]int
+
+	for i, part := range parts {
+		if len(part) != 2 {
+			return 0, fmt.Errorf("invalid time of day %q: expected HH:MM", value)
+		}
+
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid time of day %q: expected HH:MM", value)
+		}
+
+		fields[i] = n
+	}
+
+	hours, minutes, seconds := fields[0], fields[1], fields[2]
+
+	if hours == 24 && minutes == 0 && seconds == 0 {
+		return EndOfDay, nil
+	}
+
+	if hours > 23 || minutes > 59 || seconds > 59 {
+		return 0, fmt.Errorf("invalid time of day %q", value)
+	}
+
+	return TimeOfDay(hours*60*60 + minutes*60 + seconds), nil
+}
+
+// timeOfDayOf returns the time of day of t in t's location
+func timeOfDayOf(t time.Time) TimeOfDay {
+	return TimeOfDay(t.Hour()*60*60 + t.Minute()*60 + t.Second())
+}
+
+// String formats the time of day as HH:MM, or HH:MM:SS when it has seconds
+func (t TimeOfDay) String() string {
+	hours, minutes, seconds := int(t)/3600, int(t)%3600/60, int(t)%60
+
+	if seconds != 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
+	}
+
+	return fmt.Sprintf("%02d:%02d", hours, minutes)
+}
+
+// OpeningPeriod is a period in a weekly schedule.
+//
+// A period that closes at or before the time it opens runs overnight into the next day,
+// e.g 20:00 to 06:00. A period that opens at 00:00 and closes at 00:00 or 24:00 is open all day.
+type OpeningPeriod struct {
+	Day    time.Weekday
+	Opens  TimeOfDay
+	Closes TimeOfDay
+}
+
+// ParseOpeningPeriod reads a period from business hours given as a day name e.g MONDAY and HH:MM times
+func ParseOpeningPeriod(day, openingTime, closingTime string) (OpeningPeriod, error) {
+	dayOfWeek := DayOfWeek(strings.ToUpper(strings.TrimSpace(day)))
+	if !dayOfWeek.IsValid() {
+		return OpeningPeriod{}, fmt.Errorf("invalid day %q", day)
+	}
+
+	opens, err := ParseTimeOfDay(openingTime)
+	if err != nil {
+		return OpeningPeriod{}, fmt.Errorf("invalid opening time for %s: %w", dayOfWeek, err)
+	}
+
+	closes, err := ParseTimeOfDay(closingTime)
+	if err != nil {
+		return OpeningPeriod{}, fmt.Errorf("invalid closing time for %s: %w", dayOfWeek, err)
+	}
+
+	if opens == EndOfDay {
+		return OpeningPeriod{}, fmt.Errorf("invalid opening time for %s: 24:00", dayOfWeek)
+	}
+
+	// 23:59 is commonly used to mean "until midnight"
+	if closes == TimeOfDay(23*60*60+59*60) || closes == TimeOfDay(23*60*60+59*60+59) {
+		closes = EndOfDay
+	}
+
+	return OpeningPeriod{
+		Day:    dayOfWeek.Weekday(),
+		Opens:  opens,
+		Closes: closes,
+	}, nil
+}
+
+// IsAllDay reports whether the period covers the whole day
+func (p OpeningPeriod) IsAllDay() bool {
+	return p.Opens == 0 && (p.Closes == 0 || p.Closes == EndOfDay)
+}
+
+// IsOvernight reports whether the period runs past midnight into the next day
+func (p OpeningPeriod) IsOvernight() bool {
+	return !p.IsAllDay() && p.Closes <= p.Opens
+}
+
+// weekSpan returns when the period starts and ends in seconds since the start of the week (Sunday 00:00)
+func (p OpeningPeriod) weekSpan() (int, int) {
+	start := int(p.Day)*secondsPerDay + int(p.Opens)
+
+	switch {
+	case p.IsAllDay():
+		return start, start + secondsPerDay
+	case p.IsOvernight():
+		return start, int(p.Day)*secondsPerDay + secondsPerDay + int(p.Closes)
+	default:
+		return start, int(p.Day)*secondsPerDay + int(p.Closes)
+	}
+}
+
+// BusinessHoursPeriod is implemented by the business hours types that can be read into a WeeklySchedule
+type BusinessHoursPeriod interface {
+	Period() (OpeningPeriod, error)
+}
+
+// Period reads the business hours into an opening period
+func (b BusinessHours) Period() (OpeningPeriod, error) {
+	return ParseOpeningPeriod(b.Day, b.OpeningTime, b.ClosingTime)
+}
+
+// Period reads the business hours into an opening period
+func (b BusinessHoursOutput) Period() (OpeningPeriod, error) {
+	return ParseOpeningPeriod(b.Day, b.OpeningTime, b.ClosingTime)
+}
+
+// Period reads the business hours into an opening period
+func (b PractitionerBusinessHours) Period() (OpeningPeriod, error) {
+	return ParseOpeningPeriod(b.Day, b.OpeningTime, b.ClosingTime)
+}
+
+// WeeklySchedule is the weekly business hours of a facility or practitioner.
+// Times are wall clock times, so a schedule is checked against times in the facility's own time zone.
+type WeeklySchedule struct {
+	Periods []OpeningPeriod
+}
+
+// NewWeeklySchedule reads business hours e.g a facility's BusinessHoursOutput into a weekly schedule
+func NewWeeklySchedule[T BusinessHoursPeriod](hours []T) (*WeeklySchedule, error) {
+	schedule := &WeeklySchedule{
+		Periods: make([]OpeningPeriod, 0, len(hours)),
+	}
+
+	for _, h := range hours {
+		period, err := h.Period()
+		if err != nil {
+			return nil, err
+		}
+
+		schedule.Periods = append(schedule.Periods, period)
+	}
+
+	return schedule, nil
+}
+
+// Schedule reads the facility's business hours into a weekly schedule
+func (f FacilityOutput) Schedule() (*WeeklySchedule, error) {
+	return NewWeeklySchedule(f.BusinessHours)
+}
+
+// Schedule reads the practitioner's business hours into a weekly schedule
+func (p Practitioner) Schedule() (*WeeklySchedule, error) {
+	return NewWeeklySchedule(p.BusinessHours)
+}
+
+// weekSecond returns the number of seconds between the start of t's week (Sunday 00:00) and t
+func weekSecond(t time.Time) int {
+	return int(t.Weekday())*secondsPerDay + int(timeOfDayOf(t))
+}
+
+// IsOpenAt reports whether the schedule is open at t, read as a wall clock time in t's location
+func (s *WeeklySchedule) IsOpenAt(t time.Time) bool {
+	now := weekSecond(t)
+
+	for _, period := range s.Periods {
+		start, end := period.weekSpan()
+
+		// periods that run past Saturday midnight continue at the start of the week
+		if (now >= start && now < end) || (now+secondsPerWeek >= start && now+secondsPerWeek < end) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NextOpening returns the next time after t at which one of the schedule's periods starts, in t's location.
+// It returns false when the schedule has no periods.
+func (s *WeeklySchedule) NextOpening(t time.Time) (time.Time, bool) {
+	if len(s.Periods) == 0 {
+		return time.Time{}, false
+	}
+
+	now := weekSecond(t)
+	wait := -1
+
+	var next OpeningPeriod
+
+	for _, period := range s.Periods {
+		start, _ := period.weekSpan()
+
+		delta := ((start-now)%secondsPerWeek + secondsPerWeek) % secondsPerWeek
+		if delta == 0 {
+			delta = secondsPerWeek
+		}
+
+		if wait < 0 || delta < wait {
+			wait, next = delta, period
+		}
+	}
+
+	days := (now+wait)/secondsPerDay - int(t.Weekday())
+	opens := int(next.Opens)
+
+	return time.Date(t.Year(), t.Month(), t.Day()+days, opens/3600, opens%3600/60, opens%60, 0, t.Location()), true
+}
+
+// OpenAt returns the facilities in the page whose business hours are open at t in loc, the facilities' time zone.
+// Facilities without business hours or with business hours that cannot be read are left out.
+func OpenAt(page *FacilityPage, t time.Time, loc *time.Location) []FacilityOutput {
+	if page == nil {
+		return nil
+	}
+
+	if loc != nil {
+		t = t.In(loc)
+	}
+
+	var open []FacilityOutput
+
+	for _, facility := range page.Results {
+		schedule, err := facility.Schedule()
+		if err != nil || !schedule.IsOpenAt(t) {
+			continue
+		}
+
+		open = append(open, facility)
+	}
+
+	return open
+}
+
+// OpenNow returns the facilities in the page that are open now in loc, the facilities' time zone e.g Africa/Nairobi
+func OpenNow(page *FacilityPage, loc *time.Location) []FacilityOutput {
+	return OpenAt(page, time.Now(), loc)
+}
+
+// Validate checks that the day is a day of the week e.g MONDAY and that the closing time is after the opening time.
+// Hours that run past midnight are set as two periods, one on each day; 00:00 to 23:59 is open all day.
+func (b BusinessHours) Validate() error {
+	period, err := b.Period()
+	if err != nil {
+		return err
+	}
+
+	if !period.IsAllDay() && period.Closes <= period.Opens {
+		return fmt.Errorf("closing time %s must be after opening time %s on %s", b.ClosingTime, b.OpeningTime, b.normalized().Day)
+	}
+
+	return nil
+}
+
+// normalized returns the business hours with the day in the upper case form health CRM uses
+func (b BusinessHours) normalized() BusinessHours {
+	b.Day = strings.ToUpper(strings.TrimSpace(b.Day))
+
+	return b
+}
+
+// validateWeek checks each day's business hours and that periods on the same day do not overlap
+func validateWeek(hours []BusinessHours) error {
+	periods := make([]OpeningPeriod, 0, len(hours))
+
+	for _, h := range hours {
+		if err := h.Validate(); err != nil {
+			return err
+		}
+
+		period, _ := h.Period()
+		periods = append(periods, period)
+	}
+
+	sort.Slice(periods, func(i, j int) bool {
+		if periods[i].Day != periods[j].Day {
+			return periods[i].Day < periods[j].Day
+		}
+
+		return periods[i].Opens < periods[j].Opens
+	})
+
+	for i := 1; i < len(periods); i++ {
+		previous, current := periods[i-1], periods[i]
+		if previous.Day != current.Day {
+			continue
+		}
+
+		_, previousEnd := previous.weekSpan()
+		currentStart, _ := current.weekSpan()
+
+		if currentStart < previousEnd {
+			return fmt.Errorf("business hours on %s overlap", strings.ToUpper(previous.Day.String()))
+		}
+	}
+
+	return nil
+}
+
+// facilityBusinessHoursInput is used to replace all of a facility's business hours
+type facilityBusinessHoursInput struct {
+	BusinessHours []BusinessHours `json:"businesshours"`
+}
+
+// facilityBusinessHoursPath returns the path of a facility's business hours
+func facilityBusinessHoursPath(facilityID string) string {
+	return fmt.Sprintf("/v1/facilities/facilities/%s/businesshours/", facilityID)
+}
+
+// SetFacilityBusinessHours replaces a facility's business hours for the whole week.
+// Days that are left out are closed; an empty week clears the facility's business hours.
+func (h *HealthCRMLib) SetFacilityBusinessHours(ctx context.Context, facilityID string, hours []BusinessHours) (*FacilityOutput, error) {
+	if facilityID == "" {
+		return nil, errors.New("facility ID must be provided")
+	}
+
+	if err := validateWeek(hours); err != nil {
+		return nil, err
+	}
+
+	input := facilityBusinessHoursInput{
+		BusinessHours: make([]BusinessHours, 0, len(hours)),
+	}
+
+	for _, h := range hours {
+		input.BusinessHours = append(input.BusinessHours, h.normalized())
+	}
+
+	path := fmt.Sprintf("/v1/facilities/facilities/%s/", facilityID)
+
+	response, err := h.client.MakeRequest(ctx, http.MethodPatch, path, nil, input)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	respBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read response: %w", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, newResponseError(response, respBytes)
+	}
+
+	var facilityOutput *FacilityOutput
+
+	err = json.Unmarshal(respBytes, &facilityOutput)
+	if err != nil {
+		return nil, err
+	}
+
+	return facilityOutput, nil
+}
+
+// UpsertBusinessHoursForDay sets a facility's business hours for one day, leaving the rest of the week as it is.
+// The day's existing hours are updated in place; any other periods on that day are removed once the update succeeds.
+func (h *HealthCRMLib) UpsertBusinessHoursForDay(ctx context.Context, facilityID string, hours BusinessHours) (*BusinessHoursOutput, error) {
+	if facilityID == "" {
+		return nil, errors.New("facility ID must be provided")
+	}
+
+	if err := hours.Validate(); err != nil {
+		return nil, err
+	}
+
+	hours = hours.normalized()
+
+	existing, err := h.facilityBusinessHoursForDay(ctx, facilityID, hours.Day)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(existing) == 0 {
+		response, err := h.client.MakeRequest(ctx, http.MethodPost, facilityBusinessHoursPath(facilityID), nil, hours)
+		if err != nil {
+			return nil, err
+		}
+
+		return readBusinessHours(response, http.StatusCreated)
+	}
+
+	path := fmt.Sprintf("%s%s/", facilityBusinessHoursPath(facilityID), existing[0].ID)
+
+	response, err := h.client.MakeRequest(ctx, http.MethodPatch, path, nil, hours)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := readBusinessHours(response, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	// the other periods are only removed once the day's new hours are saved
+	for _, extra := range existing[1:] {
+		if err := h.deleteBusinessHours(ctx, facilityID, extra.ID); err != nil {
+			return nil, fmt.Errorf("unable to remove business hours on %s: %w", hours.Day, err)
+		}
+	}
+
+	return updated, nil
+}
+
+// RemoveBusinessHoursForDay closes a facility for a day of the week e.g SUNDAY by removing that day's business hours.
+// It does nothing when the facility has no business hours on that day.
+func (h *HealthCRMLib) RemoveBusinessHoursForDay(ctx context.Context, facilityID string, day string) error {
+	if facilityID == "" {
+		return errors.New("facility ID must be provided")
+	}
+
+	dayOfWeek := DayOfWeek(strings.ToUpper(strings.TrimSpace(day)))
+	if !dayOfWeek.IsValid() {
+		return fmt.Errorf("invalid day %q", day)
+	}
+
+	existing, err := h.facilityBusinessHoursForDay(ctx, facilityID, dayOfWeek.String())
+	if err != nil {
+		return err
+	}
+
+	for _, hours := range existing {
+		if err := h.deleteBusinessHours(ctx, facilityID, hours.ID); err != nil {
+			return fmt.Errorf("unable to remove business hours on %s: %w", dayOfWeek, err)
+		}
+	}
+
+	return nil
+}
+
+// facilityBusinessHoursForDay fetches a facility's business hours on a day, given in upper case
+func (h *HealthCRMLib) facilityBusinessHoursForDay(ctx context.Context, facilityID, day string) ([]BusinessHoursOutput, error) {
+	facility, err := h.GetFacilityByID(ctx, facilityID)
+	if err != nil {
+		return nil, err
+	}
+
+	var hours []BusinessHoursOutput
+
+	for _, h := range facility.BusinessHours {
+		if strings.EqualFold(h.Day, day) {
+			hours = append(hours, h)
+		}
+	}
+
+	return hours, nil
+}
+
+// deleteBusinessHours removes one of a facility's business hours
+func (h *HealthCRMLib) deleteBusinessHours(ctx context.Context, facilityID, hoursID string) error {
+	path := fmt.Sprintf("%s%s/", facilityBusinessHoursPath(facilityID), hoursID)
+
+	response, err := h.client.MakeRequest(ctx, http.MethodDelete, path, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	respBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("could not read response: %w", err)
+	}
+
+	if response.StatusCode != http.StatusNoContent && response.StatusCode != http.StatusOK {
+		return newResponseError(response, respBytes)
+	}
+
+	return nil
+}
+
+// readBusinessHours reads the business hours in a response that is expected to have the given status code
+func readBusinessHours(response *http.Response, expectedStatusCode int) (*BusinessHoursOutput, error) {
+	defer response.Body.Close()
+
+	respBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read response: %w", err)
+	}
+
+	if response.StatusCode != expectedStatusCode {
+		return nil, newResponseError(response, respBytes)
+	}
+
+	var hours *BusinessHoursOutput
+
+	err = json.Unmarshal(respBytes, &hours)
+	if err != nil {
+		return nil, err
+	}
+
+	return hours, nil
+}
@@ -0,0 +1,688 @@
+package healthcrm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestParseTimeOfDay(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    TimeOfDay
+		wantErr bool
+	}{
+		{value: "08:00", want: 8 * 60 * 60},
+		{value: "18:00:01", want: 18*60*60 + 1},
+		{value: "00:00", want: 0},
+		{value: "24:00", want: EndOfDay},
+		{value: "24:01", wantErr: true},
+		{value: "8:00", wantErr: true},
+		{value: "08:60", wantErr: true},
+		{value: "08-00", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseTimeOfDay(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTimeOfDay() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseTimeOfDay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOpeningPeriod(t *testing.T) {
+	tests := []struct {
+		name          string
+		day           string
+		opening       string
+		closing       string
+		want          OpeningPeriod
+		wantAllDay    bool
+		wantOvernight bool
+		wantErr       bool
+	}{
+		{
+			name:    "Happy case: day shift",
+			day:     "MONDAY",
+			opening: "08:00:00",
+			closing: "17:00:00",
+			want:    OpeningPeriod{Day: time.Monday, Opens: 8 * 60 * 60, Closes: 17 * 60 * 60},
+		},
+		{
+			name:          "Happy case: overnight shift",
+			day:           "friday",
+			opening:       "20:00",
+			closing:       "06:00",
+			want:          OpeningPeriod{Day: time.Friday, Opens: 20 * 60 * 60, Closes: 6 * 60 * 60},
+			wantOvernight: true,
+		},
+		{
+			name:       "Happy case: open all day",
+			day:        "SUNDAY",
+			opening:    "00:00",
+			closing:    "23:59",
+			want:       OpeningPeriod{Day: time.Sunday, Opens: 0, Closes: EndOfDay},
+			wantAllDay: true,
+		},
+		{
+			name:       "Happy case: open all day from midnight to midnight",
+			day:        "SUNDAY",
+			opening:    "00:00",
+			closing:    "00:00",
+			want:       OpeningPeriod{Day: time.Sunday},
+			wantAllDay: true,
+		},
+		{
+			name:    "Sad case: invalid day",
+			day:     "FUNDAY",
+			opening: "08:00",
+			closing: "17:00",
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: invalid opening time",
+			day:     "MONDAY",
+			opening: "8am",
+			closing: "17:00",
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: opens at the end of the day",
+			day:     "MONDAY",
+			opening: "24:00",
+			closing: "06:00",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOpeningPeriod(tt.day, tt.opening, tt.closing)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseOpeningPeriod() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseOpeningPeriod() = %+v, want %+v", got, tt.want)
+			}
+
+			if got.IsAllDay() != tt.wantAllDay || got.IsOvernight() != tt.wantOvernight {
+				t.Errorf("ParseOpeningPeriod() all day = %v, overnight = %v", got.IsAllDay(), got.IsOvernight())
+			}
+		})
+	}
+}
+
+// testSchedule is a clinic open on weekdays, with a night shift on Fridays and all day on Saturdays
+func testSchedule(t *testing.T) *WeeklySchedule {
+	schedule, err := NewWeeklySchedule([]BusinessHoursOutput{
+		{Day: "MONDAY", OpeningTime: "08:00:00", ClosingTime: "17:00:00"},
+		{Day: "TUESDAY", OpeningTime: "08:00:00", ClosingTime: "17:00:00"},
+		{Day: "WEDNESDAY", OpeningTime: "08:00:00", ClosingTime: "17:00:00"},
+		{Day: "THURSDAY", OpeningTime: "08:00:00", ClosingTime: "17:00:00"},
+		{Day: "FRIDAY", OpeningTime: "08:00:00", ClosingTime: "17:00:00"},
+		{Day: "FRIDAY", OpeningTime: "20:00:00", ClosingTime: "06:00:00"},
+		{Day: "SATURDAY", OpeningTime: "00:00:00", ClosingTime: "23:59:59"},
+	})
+	if err != nil {
+		t.Fatalf("unable to read schedule: %v", err)
+	}
+
+	return schedule
+}
+
+func TestWeeklySchedule_IsOpenAt(t *testing.T) {
+	schedule := testSchedule(t)
+
+	// 2025-03-10 is a Monday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 3, 10+day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{name: "before opening", at: at(0, 7, 59), want: false},
+		{name: "at opening", at: at(0, 8, 0), want: true},
+		{name: "during the day", at: at(2, 12, 30), want: true},
+		{name: "at closing", at: at(0, 17, 0), want: false},
+		{name: "between Friday shifts", at: at(4, 18, 0), want: false},
+		{name: "Friday night shift", at: at(4, 23, 0), want: true},
+		{name: "Friday night shift after midnight", at: at(5, 3, 0), want: true},
+		{name: "Saturday", at: at(5, 15, 0), want: true},
+		{name: "Saturday just before midnight", at: at(5, 23, 59), want: true},
+		{name: "Sunday", at: at(6, 12, 0), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := schedule.IsOpenAt(tt.at); got != tt.want {
+				t.Errorf("WeeklySchedule.IsOpenAt(%v) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeeklySchedule_IsOpenAt_OvernightIntoNextWeek(t *testing.T) {
+	schedule, err := NewWeeklySchedule([]BusinessHours{
+		{Day: "SATURDAY", OpeningTime: "22:00", ClosingTime: "02:00"},
+	})
+	if err != nil {
+		t.Fatalf("unable to read schedule: %v", err)
+	}
+
+	// 2025-03-16 is a Sunday
+	if !schedule.IsOpenAt(time.Date(2025, 3, 16, 1, 0, 0, 0, time.UTC)) {
+		t.Errorf("WeeklySchedule.IsOpenAt() expected the Saturday night shift to be open early on Sunday")
+	}
+
+	if schedule.IsOpenAt(time.Date(2025, 3, 16, 2, 0, 0, 0, time.UTC)) {
+		t.Errorf("WeeklySchedule.IsOpenAt() expected the Saturday night shift to be closed at 02:00 on Sunday")
+	}
+}
+
+func TestWeeklySchedule_NextOpening(t *testing.T) {
+	schedule := testSchedule(t)
+	nairobi := time.FixedZone("EAT", 3*60*60)
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want time.Time
+	}{
+		{
+			name: "later the same day",
+			at:   time.Date(2025, 3, 10, 6, 0, 0, 0, nairobi),
+			want: time.Date(2025, 3, 10, 8, 0, 0, 0, nairobi),
+		},
+		{
+			name: "next day",
+			at:   time.Date(2025, 3, 10, 17, 30, 0, 0, nairobi),
+			want: time.Date(2025, 3, 11, 8, 0, 0, 0, nairobi),
+		},
+		{
+			name: "Friday night shift",
+			at:   time.Date(2025, 3, 14, 17, 30, 0, 0, nairobi),
+			want: time.Date(2025, 3, 14, 20, 0, 0, 0, nairobi),
+		},
+		{
+			name: "over the weekend into the next month",
+			at:   time.Date(2025, 3, 30, 12, 0, 0, 0, nairobi),
+			want: time.Date(2025, 3, 31, 8, 0, 0, 0, nairobi),
+		},
+		{
+			name: "while open",
+			at:   time.Date(2025, 3, 10, 8, 0, 0, 0, nairobi),
+			want: time.Date(2025, 3, 11, 8, 0, 0, 0, nairobi),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := schedule.NextOpening(tt.at)
+			if !ok || !got.Equal(tt.want) || got.Location() != nairobi {
+				t.Errorf("WeeklySchedule.NextOpening(%v) = %v, %v, want %v", tt.at, got, ok, tt.want)
+			}
+		})
+	}
+
+	if _, ok := (&WeeklySchedule{}).NextOpening(time.Now()); ok {
+		t.Errorf("WeeklySchedule.NextOpening() expected no opening for an empty schedule")
+	}
+}
+
+func TestOpenAt(t *testing.T) {
+	nairobi := time.FixedZone("EAT", 3*60*60)
+
+	page := &FacilityPage{
+		Results: []FacilityOutput{
+			{ID: "day", BusinessHours: []BusinessHoursOutput{{Day: "MONDAY", OpeningTime: "08:00:00", ClosingTime: "17:00:00"}}},
+			{ID: "always", BusinessHours: []BusinessHoursOutput{{Day: "MONDAY", OpeningTime: "00:00", ClosingTime: "24:00"}}},
+			{ID: "no-hours"},
+			{ID: "unreadable", BusinessHours: []BusinessHoursOutput{{Day: "MONDAY", OpeningTime: "8am", ClosingTime: "5pm"}}},
+		},
+	}
+
+	// 06:00 UTC on Monday is 09:00 in Nairobi
+	at := time.Date(2025, 3, 10, 6, 0, 0, 0, time.UTC)
+
+	var ids []string
+	for _, facility := range OpenAt(page, at, nairobi) {
+		ids = append(ids, facility.ID)
+	}
+
+	if len(ids) != 2 || ids[0] != "day" || ids[1] != "always" {
+		t.Errorf("OpenAt() = %v, want the day clinic and the 24h facility", ids)
+	}
+
+	if got := OpenAt(page, at, time.UTC); len(got) != 1 || got[0].ID != "always" {
+		t.Errorf("OpenAt() in UTC = %v, want only the 24h facility", got)
+	}
+
+	if got := OpenAt(nil, at, nairobi); got != nil {
+		t.Errorf("OpenAt() = %v, want nil for a nil page", got)
+	}
+}
+
+func TestBusinessHours_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		hours   BusinessHours
+		wantErr bool
+	}{
+		{
+			name:  "Happy case: day shift",
+			hours: BusinessHours{Day: "MONDAY", OpeningTime: "08:00", ClosingTime: "17:00"},
+		},
+		{
+			name:  "Happy case: lower case day",
+			hours: BusinessHours{Day: "monday", OpeningTime: "08:00", ClosingTime: "17:00"},
+		},
+		{
+			name:  "Happy case: open all day",
+			hours: BusinessHours{Day: "SUNDAY", OpeningTime: "00:00", ClosingTime: "23:59"},
+		},
+		{
+			name:    "Sad case: invalid day",
+			hours:   BusinessHours{Day: "HOLIDAY", OpeningTime: "08:00", ClosingTime: "17:00"},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: closes before it opens",
+			hours:   BusinessHours{Day: "MONDAY", OpeningTime: "17:00", ClosingTime: "08:00"},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: closes when it opens",
+			hours:   BusinessHours{Day: "MONDAY", OpeningTime: "08:00", ClosingTime: "08:00"},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: invalid time",
+			hours:   BusinessHours{Day: "MONDAY", OpeningTime: "8am", ClosingTime: "17:00"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.hours.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("BusinessHours.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateWeek(t *testing.T) {
+	tests := []struct {
+		name    string
+		hours   []BusinessHours
+		wantErr bool
+	}{
+		{
+			name: "Happy case: split shift",
+			hours: []BusinessHours{
+				{Day: "MONDAY", OpeningTime: "08:00", ClosingTime: "12:00"},
+				{Day: "MONDAY", OpeningTime: "14:00", ClosingTime: "18:00"},
+				{Day: "TUESDAY", OpeningTime: "08:00", ClosingTime: "18:00"},
+			},
+		},
+		{
+			name: "Happy case: back to back periods",
+			hours: []BusinessHours{
+				{Day: "MONDAY", OpeningTime: "12:00", ClosingTime: "18:00"},
+				{Day: "MONDAY", OpeningTime: "08:00", ClosingTime: "12:00"},
+			},
+		},
+		{
+			name: "Sad case: overlapping periods",
+			hours: []BusinessHours{
+				{Day: "MONDAY", OpeningTime: "08:00", ClosingTime: "13:00"},
+				{Day: "monday", OpeningTime: "12:00", ClosingTime: "18:00"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Sad case: invalid period",
+			hours: []BusinessHours{
+				{Day: "MONDAY", OpeningTime: "08:00", ClosingTime: "13:00"},
+				{Day: "TUESDAY", OpeningTime: "18:00", ClosingTime: "13:00"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateWeek(tt.hours); (err != nil) != tt.wantErr {
+				t.Errorf("validateWeek() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHealthCRMLib_SetFacilityBusinessHours(t *testing.T) {
+	ctx := context.Background()
+	path := fmt.Sprintf("%s/v1/facilities/facilities/123/", baseURL)
+
+	tests := []struct {
+		name     string
+		hours    []BusinessHours
+		wantDays []string
+		wantSent bool
+		wantErr  bool
+	}{
+		{
+			name: "Happy case: replace the week",
+			hours: []BusinessHours{
+				{Day: "monday", OpeningTime: "08:00", ClosingTime: "17:00"},
+				{Day: "SATURDAY", OpeningTime: "09:00", ClosingTime: "13:00"},
+			},
+			wantDays: []string{"MONDAY", "SATURDAY"},
+			wantSent: true,
+		},
+		{
+			name:     "Happy case: clear the week",
+			hours:    []BusinessHours{},
+			wantDays: []string{},
+			wantSent: true,
+		},
+		{
+			name: "Sad case: closing time before opening time is not sent",
+			hours: []BusinessHours{
+				{Day: "MONDAY", OpeningTime: "17:00", ClosingTime: "08:00"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			MockAuthenticate()
+
+			var sent facilityBusinessHoursInput
+
+			httpmock.RegisterResponder(http.MethodPatch, path, func(r *http.Request) (*http.Response, error) {
+				var raw map[string]json.RawMessage
+				if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+					return nil, err
+				}
+
+				if _, ok := raw["businesshours"]; !ok {
+					return httpmock.NewStringResponse(http.StatusBadRequest, `{"businesshours": ["This field is required."]}`), nil
+				}
+
+				if err := json.Unmarshal(raw["businesshours"], &sent.BusinessHours); err != nil {
+					return nil, err
+				}
+
+				return httpmock.NewJsonResponse(http.StatusOK, &FacilityOutput{ID: "123"})
+			})
+
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
+			if err != nil {
+				t.Fatalf("unable to initialize sdk: %v", err)
+			}
+			defer h.Close()
+
+			_, err = h.SetFacilityBusinessHours(ctx, "123", tt.hours)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HealthCRMLib.SetFacilityBusinessHours() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if count := httpmock.GetCallCountInfo()[http.MethodPatch+" "+path]; (count != 0) != tt.wantSent {
+				t.Fatalf("HealthCRMLib.SetFacilityBusinessHours() sent %d requests", count)
+			}
+
+			if !tt.wantSent {
+				return
+			}
+
+			if len(sent.BusinessHours) != len(tt.wantDays) {
+				t.Fatalf("HealthCRMLib.SetFacilityBusinessHours() sent %v", sent.BusinessHours)
+			}
+
+			for i, day := range tt.wantDays {
+				if sent.BusinessHours[i].Day != day {
+					t.Errorf("HealthCRMLib.SetFacilityBusinessHours() sent day %s, want %s", sent.BusinessHours[i].Day, day)
+				}
+			}
+		})
+	}
+}
+
+// registerFacilityHoursResponders mocks a facility with the given business hours and the business hours endpoints
+func registerFacilityHoursResponders(hours []BusinessHoursOutput) {
+	facilityPath := fmt.Sprintf("%s/v1/facilities/facilities/123/", baseURL)
+	hoursPath := fmt.Sprintf("%s/v1/facilities/facilities/123/businesshours/", baseURL)
+
+	httpmock.RegisterResponder(http.MethodGet, facilityPath, func(r *http.Request) (*http.Response, error) {
+		return httpmock.NewJsonResponse(http.StatusOK, &FacilityOutput{ID: "123", BusinessHours: hours})
+	})
+
+	httpmock.RegisterResponder(http.MethodPost, hoursPath, func(r *http.Request) (*http.Response, error) {
+		var input BusinessHours
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			return nil, err
+		}
+
+		return httpmock.NewJsonResponse(http.StatusCreated, &BusinessHoursOutput{
+			ID: "new", Day: input.Day, OpeningTime: input.OpeningTime, ClosingTime: input.ClosingTime, FacilityID: "123",
+		})
+	})
+
+	for _, h := range hours {
+		path := fmt.Sprintf("%s%s/", hoursPath, h.ID)
+
+		httpmock.RegisterResponder(http.MethodPatch, path, func(r *http.Request) (*http.Response, error) {
+			var input BusinessHours
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+				return nil, err
+			}
+
+			return httpmock.NewJsonResponse(http.StatusOK, &BusinessHoursOutput{
+				ID: h.ID, Day: input.Day, OpeningTime: input.OpeningTime, ClosingTime: input.ClosingTime, FacilityID: "123",
+			})
+		})
+
+		httpmock.RegisterResponder(http.MethodDelete, path, httpmock.NewStringResponder(http.StatusNoContent, ""))
+	}
+}
+
+func TestHealthCRMLib_UpsertBusinessHoursForDay(t *testing.T) {
+	ctx := context.Background()
+	hoursPath := fmt.Sprintf("%s/v1/facilities/facilities/123/businesshours/", baseURL)
+
+	existing := []BusinessHoursOutput{
+		{ID: "mon", Day: "MONDAY", OpeningTime: "08:00:00", ClosingTime: "17:00:00"},
+		{ID: "sat-am", Day: "SATURDAY", OpeningTime: "08:00:00", ClosingTime: "12:00:00"},
+		{ID: "sat-pm", Day: "SATURDAY", OpeningTime: "14:00:00", ClosingTime: "17:00:00"},
+	}
+
+	tests := []struct {
+		name        string
+		hours       BusinessHours
+		wantID      string
+		wantCalls   map[string]int
+		rejectPatch bool
+		wantErr     bool
+		wantNoCalls bool
+	}{
+		{
+			name:   "Happy case: update a day's hours",
+			hours:  BusinessHours{Day: "monday", OpeningTime: "07:00", ClosingTime: "19:00"},
+			wantID: "mon",
+			wantCalls: map[string]int{
+				http.MethodPatch + " " + hoursPath + "mon/": 1,
+				http.MethodPost + " " + hoursPath:           0,
+			},
+		},
+		{
+			name:   "Happy case: add hours on a closed day",
+			hours:  BusinessHours{Day: "SUNDAY", OpeningTime: "10:00", ClosingTime: "14:00"},
+			wantID: "new",
+			wantCalls: map[string]int{
+				http.MethodPost + " " + hoursPath: 1,
+			},
+		},
+		{
+			name:   "Happy case: replace a split shift",
+			hours:  BusinessHours{Day: "SATURDAY", OpeningTime: "09:00", ClosingTime: "13:00"},
+			wantID: "sat-am",
+			wantCalls: map[string]int{
+				http.MethodPatch + " " + hoursPath + "sat-am/":  1,
+				http.MethodDelete + " " + hoursPath + "sat-pm/": 1,
+			},
+		},
+		{
+			name:        "Sad case: rejected update keeps the split shift",
+			hours:       BusinessHours{Day: "SATURDAY", OpeningTime: "09:00", ClosingTime: "13:00"},
+			rejectPatch: true,
+			wantErr:     true,
+			wantCalls: map[string]int{
+				http.MethodPatch + " " + hoursPath + "sat-am/":  1,
+				http.MethodDelete + " " + hoursPath + "sat-pm/": 0,
+			},
+		},
+		{
+			name:        "Sad case: invalid day",
+			hours:       BusinessHours{Day: "HOLIDAY", OpeningTime: "09:00", ClosingTime: "13:00"},
+			wantErr:     true,
+			wantNoCalls: true,
+		},
+		{
+			name:        "Sad case: closing time before opening time",
+			hours:       BusinessHours{Day: "MONDAY", OpeningTime: "13:00", ClosingTime: "09:00"},
+			wantErr:     true,
+			wantNoCalls: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			MockAuthenticate()
+			registerFacilityHoursResponders(existing)
+
+			if tt.rejectPatch {
+				httpmock.RegisterResponder(http.MethodPatch, hoursPath+"sat-am/", httpmock.NewStringResponder(http.StatusBadRequest, `{"opening_time": ["Invalid."]}`))
+			}
+
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
+			if err != nil {
+				t.Fatalf("unable to initialize sdk: %v", err)
+			}
+			defer h.Close()
+
+			got, err := h.UpsertBusinessHoursForDay(ctx, "123", tt.hours)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HealthCRMLib.UpsertBusinessHoursForDay() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			calls := httpmock.GetCallCountInfo()
+
+			if tt.wantNoCalls {
+				if count := calls[http.MethodGet+" "+baseURL+"/v1/facilities/facilities/123/"]; count != 0 {
+					t.Errorf("invalid business hours sent %d requests, want none", count)
+				}
+
+				return
+			}
+
+			for call, want := range tt.wantCalls {
+				if calls[call] != want {
+					t.Errorf("%s called %d times, want %d", call, calls[call], want)
+				}
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got.ID != tt.wantID || got.Day != tt.hours.normalized().Day || got.OpeningTime != tt.hours.OpeningTime {
+				t.Errorf("HealthCRMLib.UpsertBusinessHoursForDay() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestHealthCRMLib_RemoveBusinessHoursForDay(t *testing.T) {
+	ctx := context.Background()
+	hoursPath := fmt.Sprintf("%s/v1/facilities/facilities/123/businesshours/", baseURL)
+
+	existing := []BusinessHoursOutput{
+		{ID: "mon", Day: "MONDAY", OpeningTime: "08:00:00", ClosingTime: "17:00:00"},
+		{ID: "sat-am", Day: "SATURDAY", OpeningTime: "08:00:00", ClosingTime: "12:00:00"},
+		{ID: "sat-pm", Day: "SATURDAY", OpeningTime: "14:00:00", ClosingTime: "17:00:00"},
+	}
+
+	tests := []struct {
+		name        string
+		day         string
+		wantDeleted []string
+		wantErr     bool
+	}{
+		{
+			name:        "Happy case: remove a day's hours",
+			day:         "saturday",
+			wantDeleted: []string{"sat-am", "sat-pm"},
+		},
+		{
+			name: "Happy case: day without hours",
+			day:  "SUNDAY",
+		},
+		{
+			name:    "Sad case: invalid day",
+			day:     "WEEKEND",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			MockAuthenticate()
+			registerFacilityHoursResponders(existing)
+
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
+			if err != nil {
+				t.Fatalf("unable to initialize sdk: %v", err)
+			}
+			defer h.Close()
+
+			if err := h.RemoveBusinessHoursForDay(ctx, "123", tt.day); (err != nil) != tt.wantErr {
+				t.Fatalf("HealthCRMLib.RemoveBusinessHoursForDay() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			calls := httpmock.GetCallCountInfo()
+			deleted := 0
+
+			for _, hours := range existing {
+				deleted += calls[http.MethodDelete+" "+hoursPath+hours.ID+"/"]
+			}
+
+			if deleted != len(tt.wantDeleted) {
+				t.Errorf("HealthCRMLib.RemoveBusinessHoursForDay() deleted %d business hours, want %d", deleted, len(tt.wantDeleted))
+			}
+
+			for _, id := range tt.wantDeleted {
+				if calls[http.MethodDelete+" "+hoursPath+id+"/"] != 1 {
+					t.Errorf("HealthCRMLib.RemoveBusinessHoursForDay() did not delete %s", id)
+				}
+			}
+		})
+	}
+}
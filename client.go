@@ -4,165 +4,407 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math/rand/v2"
 	"net/http"
 	"net/url"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/savannahghi/authutils"
-	"github.com/savannahghi/serverutils"
 	"github.com/sirupsen/logrus"
 )
 
+// jsonContentType is the content type of request bodies sent by MakeRequest
+const jsonContentType = "application/json"
+
 var (
+	// accessTokenTimeout is used to schedule the refresh when the auth server does not return expires_in
 	accessTokenTimeout = 59 * time.Minute
-)
 
-// IAuthUtilsLib holds the method defined in authutils library
-type authUtilsLib interface {
-	Authenticate() (*authutils.OAUTHResponse, error)
-	RefreshToken(ctx context.Context, refreshToken string) (*authutils.OAUTHResponse, error)
-}
+	// minimumRefreshInterval keeps very short-lived tokens from turning the refresher into a busy loop
+	minimumRefreshInterval = time.Second
+
+	// ErrClientClosed is returned when the library is used after it has been closed
+	ErrClientClosed = errors.New("health CRM client is closed")
+)
 
 // client is the library's client used to make requests
 type client struct {
-	authClient        authUtilsLib
+	baseURL           string
+	tokenSource       TokenSource
 	httpClient        *http.Client
-	refreshToken      string
+	logger            logrus.FieldLogger
+	refreshMargin     time.Duration
+	refreshJitter     time.Duration
+	retryPolicy       RetryPolicy
+	rateLimiter       *rateLimiter
+	tokenMu           sync.Mutex   // serializes calls to the token source
+	mu                sync.RWMutex // guards the token state below
 	accessToken       string
+	tokenExpiry       time.Time
 	accessTokenTicker *time.Ticker
 	authFailed        bool
+	lastRefresh       time.Time
+	lastRefreshErr    error
+	closed            atomic.Bool
+	stopBackground    context.CancelFunc
+	backgroundDone    chan struct{}
 }
 
 // newClient is the constructor which initializes health crm's authentication mechanism
-func newClient() (*client, error) {
-	config := authutils.Config{
-		AuthServerEndpoint: serverutils.MustGetEnvVar("HEALTH_CRM_AUTH_SERVER_ENDPOINT"),
-		ClientID:           serverutils.MustGetEnvVar("HEALTH_CRM_CLIENT_ID"),
-		ClientSecret:       serverutils.MustGetEnvVar("HEALTH_CRM_CLIENT_SECRET"),
-		GrantType:          serverutils.MustGetEnvVar("HEALTH_CRM_GRANT_TYPE"),
-		Username:           serverutils.MustGetEnvVar("HEALTH_CRM_USERNAME"),
-		Password:           serverutils.MustGetEnvVar("HEALTH_CRM_PASSWORD"),
-	}
-	slade360AuthClient, err := authutils.NewClient(config)
-	if err != nil {
-		return nil, err
+func newClient(opts *options) (*client, error) {
+	tokenSource := opts.tokenSource
+	if tokenSource == nil {
+		slade360AuthClient, err := authutils.NewClient(opts.authConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		tokenSource = &authUtilsTokenSource{
+			authClient: slade360AuthClient,
+			logger:     opts.logger,
+		}
 	}
 
 	c := client{
-		authClient: slade360AuthClient,
-		httpClient: &http.Client{
-			Timeout: time.Second * 10,
-		},
-		accessToken:  "",
-		refreshToken: "",
-		authFailed:   false,
+		baseURL:       opts.baseURL,
+		tokenSource:   tokenSource,
+		httpClient:    opts.httpClient,
+		logger:        opts.logger,
+		refreshMargin: opts.tokenRefreshMargin,
+		refreshJitter: opts.tokenRefreshJitter,
+		retryPolicy:   opts.retryPolicy,
+		rateLimiter:   newRateLimiter(opts.rateLimit, opts.endpointRateLimits),
+		accessToken:   "",
+		authFailed:    false,
 	}
 
-	err = c.login()
+	err := c.fetchToken(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
 	// set up background routine to update tokens
-	go c.background()
+	ctx, cancel := context.WithCancel(context.Background())
+	c.stopBackground = cancel
+	c.backgroundDone = make(chan struct{})
+
+	go c.background(ctx)
 
 	return &c, nil
 }
 
-// executed as a go routine to update access and refresh token
-func (c *client) background() {
-	for t := range c.accessTokenTicker.C {
-		logrus.Println("HealthCRM Access Token updated at: ", t)
+// executed as a go routine to update access and refresh token until ctx is cancelled
+func (c *client) background(ctx context.Context) {
+	defer close(c.backgroundDone)
 
-		err := c.refreshAccessToken()
-		if err != nil {
-			c.authFailed = true
-		} else {
-			c.authFailed = false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case t := <-c.accessTokenTicker.C:
+			c.logger.Println("HealthCRM Access Token updated at: ", t)
+
+			c.tokenMu.Lock()
+			err := c.fetchToken(ctx)
+			c.tokenMu.Unlock()
+
+			c.setRefreshResult(err)
 		}
 	}
 }
 
-// setAccessToken sets the access token and updates the ticker timer
-func (c *client) setRefreshAndAccessToken(token *authutils.OAUTHResponse) {
+// close stops the background token refresher and releases idle connections.
+// It waits for the refresher to exit until ctx is done.
+func (c *client) close(ctx context.Context) error {
+	if !c.closed.CompareAndSwap(false, true) {
+		return ErrClientClosed
+	}
+
+	if c.stopBackground != nil {
+		c.stopBackground()
+	}
+
+	c.mu.Lock()
+	if c.accessTokenTicker != nil {
+		c.accessTokenTicker.Stop()
+	}
+	c.mu.Unlock()
+
+	c.httpClient.CloseIdleConnections()
+
+	if c.backgroundDone == nil {
+		return nil
+	}
+
+	select {
+	case <-c.backgroundDone:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// getAccessToken returns the access token used to authorize requests
+func (c *client) getAccessToken() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.accessToken
+}
+
+// setRefreshResult records the outcome of the last attempt to refresh the tokens
+func (c *client) setRefreshResult(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.authFailed = err != nil
+	c.lastRefreshErr = err
+}
+
+// getTokenExpiry returns the time at which the current access token expires
+func (c *client) getTokenExpiry() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.tokenExpiry
+}
+
+// refreshInterval returns how long to wait before refreshing a token that expires in expiresIn.
+// The refresh happens margin before expiry, brought forward by a random amount of up to jitter
+// so that many clients sharing credentials do not refresh at the same instant.
+func refreshInterval(expiresIn, margin, jitter time.Duration) time.Duration {
+	if expiresIn <= 0 {
+		return accessTokenTimeout
+	}
+
+	interval := expiresIn - margin
+	if jitter > 0 {
+		interval -= rand.N(jitter) //nolint:gosec
+	}
+
+	// the margin and jitter are larger than the token's lifetime
+	if interval <= 0 {
+		interval = expiresIn / 2
+	}
+
+	return max(interval, minimumRefreshInterval)
+}
+
+// setToken sets the access token and schedules its refresh from the token's expiry
+func (c *client) setToken(token *Token) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresIn time.Duration
+	if !token.Expiry.IsZero() {
+		expiresIn = time.Until(token.Expiry)
+	}
+
 	c.accessToken = token.AccessToken
-	c.refreshToken = token.RefreshToken
+	c.tokenExpiry = token.Expiry
+	c.lastRefresh = time.Now()
+
+	if c.closed.Load() {
+		return
+	}
+
+	interval := refreshInterval(expiresIn, c.refreshMargin, c.refreshJitter)
 
 	if c.accessTokenTicker != nil {
-		c.accessTokenTicker.Reset(accessTokenTimeout)
+		c.accessTokenTicker.Reset(interval)
 	} else {
-		c.accessTokenTicker = time.NewTicker(accessTokenTimeout)
+		c.accessTokenTicker = time.NewTicker(interval)
 	}
 }
 
-// login uses the provided credentials to login to the authserver backend
-// It obtains the necessary tokens required to make authenticated requests
-func (c *client) login() error {
-	token, err := c.authClient.Authenticate()
+// fetchToken obtains a new access token from the token source.
+// Callers other than the constructor must hold tokenMu.
+func (c *client) fetchToken(ctx context.Context) error {
+	token, err := c.tokenSource.Token(ctx)
 	if err != nil {
 		return err
 	}
 
-	c.setRefreshAndAccessToken(token)
+	if token == nil || token.AccessToken == "" {
+		return errors.New("token source returned an empty access token")
+	}
+
+	c.setToken(token)
 
 	return nil
 }
 
-// refreshAccessToken makes a request to get
-// new access and refresh tokens
-func (c *client) refreshAccessToken() error {
-	ctx := context.Background()
+// reauthenticate obtains a new token after the server rejected staleAccessToken.
+// Concurrent callers that were rejected with the same token share a single call to the token source.
+func (c *client) reauthenticate(ctx context.Context, staleAccessToken string) error {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
 
-	token, err := c.authClient.RefreshToken(ctx, c.refreshToken)
-	if err != nil {
-		return err
+	// another caller has already replaced the rejected token
+	if c.getAccessToken() != staleAccessToken {
+		return nil
 	}
 
-	c.setRefreshAndAccessToken(token)
+	err := c.fetchToken(ctx)
+	c.setRefreshResult(err)
 
-	return nil
+	return err
 }
 
 // MakeRequest performs a HTTP request to the provided path and parameters
+//
+// Failed requests are retried according to the client's retry policy.
+// If health CRM responds with 401 Unauthorized, the tokens are renewed and the request is replayed once
 func (c *client) MakeRequest(ctx context.Context, method, path string, queryParams url.Values, body interface{}) (*http.Response, error) {
-	urlPath := fmt.Sprintf("%s%s", BaseURL, path)
+	if c.closed.Load() {
+		return nil, ErrClientClosed
+	}
+
+	var encoded []byte
 
-	var request *http.Request
 	switch method {
-	case http.MethodGet:
-		req, err := http.NewRequestWithContext(ctx, method, urlPath, nil)
-		if err != nil {
-			return nil, err
-		}
-		request = req
+	case http.MethodGet, http.MethodDelete:
+		// GET and DELETE requests are sent without a body
 
 	case http.MethodPost, http.MethodPatch:
-		encoded, err := json.Marshal(body)
+		payload, err := json.Marshal(body)
 		if err != nil {
 			return nil, err
 		}
 
-		payload := bytes.NewBuffer(encoded)
+		encoded = payload
 
-		req, err := http.NewRequestWithContext(ctx, method, urlPath, payload)
-		if err != nil {
+	default:
+		return nil, fmt.Errorf("s.MakeRequest() unsupported http method: %s", method)
+	}
+
+	return c.sendAuthenticated(ctx, method, path, queryParams, encoded, jsonContentType)
+}
+
+// MakeMultipartRequest sends an already encoded multipart/form-data body e.g a file upload.
+// contentType is the multipart writer's form data content type, which carries the boundary.
+// It is retried and replayed on 401 Unauthorized in the same way as MakeRequest.
+func (c *client) MakeMultipartRequest(ctx context.Context, method, path string, body []byte, contentType string) (*http.Response, error) {
+	if c.closed.Load() {
+		return nil, ErrClientClosed
+	}
+
+	if method != http.MethodPost && method != http.MethodPatch {
+		return nil, fmt.Errorf("s.MakeMultipartRequest() unsupported http method: %s", method)
+	}
+
+	return c.sendAuthenticated(ctx, method, path, nil, body, contentType)
+}
+
+// sendAuthenticated sends a request with the current access token.
+// If health CRM responds with 401 Unauthorized, the tokens are renewed and the request is replayed once
+func (c *client) sendAuthenticated(ctx context.Context, method, path string, queryParams url.Values, body []byte, contentType string) (*http.Response, error) {
+	accessToken := c.getAccessToken()
+
+	response, err := c.send(ctx, method, path, queryParams, body, contentType, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusUnauthorized {
+		return response, nil
+	}
+
+	_, _ = io.Copy(io.Discard, response.Body)
+	response.Body.Close()
+
+	err = c.reauthenticate(ctx, accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("unable to re-authenticate with health CRM: %w", err)
+	}
+
+	return c.send(ctx, method, path, queryParams, body, contentType, c.getAccessToken())
+}
+
+// send sends a request, retrying it on connection errors and retryable statuses as allowed by the retry policy.
+// It stops retrying once ctx is done or when the next attempt would start after ctx's deadline.
+func (c *client) send(ctx context.Context, method, path string, queryParams url.Values, body []byte, contentType, accessToken string) (*http.Response, error) {
+	policy := c.retryPolicy
+	retryable := policy.retryable(method, idempotencyKeyFromContext(ctx) != "")
+
+	for attempt := 1; ; attempt++ {
+		response, err := c.do(ctx, method, path, queryParams, body, contentType, accessToken)
+		if !retryable || attempt >= policy.MaxAttempts || ctx.Err() != nil {
+			return response, err
+		}
+
+		if err == nil && !policy.retryableStatus(response.StatusCode) {
+			return response, nil
+		}
+
+		wait := policy.backoff(attempt, response)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return response, err
+		}
+
+		if response != nil {
+			_, _ = io.Copy(io.Discard, response.Body)
+			response.Body.Close()
+		}
+
+		c.logger.Debugf("retrying HealthCRM %s %s in %v (attempt %d): status=%v err=%v", method, path, wait, attempt+1, statusOf(response), err)
+
+		if err := sleep(ctx, wait); err != nil {
 			return nil, err
 		}
+	}
+}
 
-		request = req
+// statusOf returns the response's status code or zero when there is no response
+func statusOf(response *http.Response) int {
+	if response == nil {
+		return 0
+	}
 
-	default:
-		return nil, fmt.Errorf("s.MakeRequest() unsupported http method: %s", method)
+	return response.StatusCode
+}
+
+// do builds and sends a single request with the body, if any, sent as contentType
+func (c *client) do(ctx context.Context, method, path string, queryParams url.Values, body []byte, contentType, accessToken string) (*http.Response, error) {
+	urlPath := fmt.Sprintf("%s%s", c.baseURL, path)
+
+	var payload io.Reader
+	if body != nil {
+		payload = bytes.NewReader(body)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, urlPath, payload)
+	if err != nil {
+		return nil, err
 	}
 
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Content-Type", "application/json")
-	request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.accessToken))
+	request.Header.Set("Content-Type", contentType)
+	request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	if key := idempotencyKeyFromContext(ctx); key != "" {
+		request.Header.Set(idempotencyKeyHeader, key)
+	}
 
 	if queryParams != nil {
 		request.URL.RawQuery = queryParams.Encode()
 	}
 
-	return c.httpClient.Do(request)
+	response, err := c.sendLimited(ctx, request, path)
+	if err != nil {
+		return nil, err
+	}
+
+	// keep the request around so that API errors can report what was sent
+	if response.Request == nil {
+		response.Request = request
+	}
+
+	return response, nil
 }
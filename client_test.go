@@ -3,12 +3,19 @@ package healthcrm
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/jarcoal/httpmock"
 	"github.com/savannahghi/authutils"
+	"github.com/sirupsen/logrus"
 )
 
 // MockAuthUtilsLib is a mock implementation of the authUtilsLib interface
@@ -126,8 +133,8 @@ func TestMakeRequest(t *testing.T) {
 			defer httpmock.DeactivateAndReset()
 
 			mockClient := &client{
-				authClient: &MockAuthUtilsLib{},
-				httpClient: &http.Client{},
+				tokenSource: &authUtilsTokenSource{authClient: &MockAuthUtilsLib{}},
+				httpClient:  &http.Client{},
 			}
 
 			response, err := mockClient.MakeRequest(ctx, tt.method, tt.path, tt.queryParams, tt.body)
@@ -144,3 +151,444 @@ func TestMakeRequest(t *testing.T) {
 		})
 	}
 }
+
+// newTestClient creates a client for https://healthcrm.test that obtains its tokens from authClient
+func newTestClient(t *testing.T, authClient authUtilsLib) *client {
+	c := &client{
+		baseURL: "https://healthcrm.test",
+		tokenSource: &authUtilsTokenSource{
+			authClient: authClient,
+			logger:     logrus.StandardLogger(),
+		},
+		httpClient: &http.Client{},
+		logger:     logrus.StandardLogger(),
+	}
+
+	if err := c.fetchToken(context.Background()); err != nil {
+		t.Fatalf("unable to obtain a token: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = c.close(context.Background())
+	})
+
+	return c
+}
+
+// rotatingAuthUtilsLib issues a new token on every refresh
+type rotatingAuthUtilsLib struct {
+	issued atomic.Int64
+}
+
+// Authenticate mocks implementation of authutil's library
+func (m *rotatingAuthUtilsLib) Authenticate() (*authutils.OAUTHResponse, error) {
+	return m.next(), nil
+}
+
+// RefreshToken mocks implementation of authutil's library
+func (m *rotatingAuthUtilsLib) RefreshToken(ctx context.Context, refreshToken string) (*authutils.OAUTHResponse, error) {
+	return m.next(), nil
+}
+
+func (m *rotatingAuthUtilsLib) next() *authutils.OAUTHResponse {
+	n := m.issued.Add(1)
+
+	return &authutils.OAUTHResponse{
+		AccessToken:  fmt.Sprintf("access-%d", n),
+		RefreshToken: fmt.Sprintf("refresh-%d", n),
+	}
+}
+
+// TestMakeRequest_ConcurrentTokenRefresh is meant to be run with the race detector (go test -race)
+func TestMakeRequest_ConcurrentTokenRefresh(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	path := "https://healthcrm.test/v1/facilities/facilities/"
+	httpmock.RegisterResponder(http.MethodGet, path, func(req *http.Request) (*http.Response, error) {
+		if !strings.HasPrefix(req.Header.Get("Authorization"), "Bearer access-") {
+			return httpmock.NewStringResponse(http.StatusUnauthorized, ""), nil
+		}
+
+		return httpmock.NewStringResponse(http.StatusOK, "{}"), nil
+	})
+
+	mockClient := newTestClient(t, &rotatingAuthUtilsLib{})
+
+	ctx := context.Background()
+	done := make(chan struct{})
+
+	var refreshers sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		refreshers.Add(1)
+		go func() {
+			defer refreshers.Done()
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					mockClient.tokenMu.Lock()
+					err := mockClient.fetchToken(ctx)
+					mockClient.tokenMu.Unlock()
+
+					mockClient.setRefreshResult(err)
+				}
+			}
+		}()
+	}
+
+	var requests sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		requests.Add(1)
+		go func() {
+			defer requests.Done()
+
+			for j := 0; j < 20; j++ {
+				response, err := mockClient.MakeRequest(ctx, http.MethodGet, "/v1/facilities/facilities/", nil, nil)
+				if err != nil {
+					t.Errorf("Error making request: %v", err)
+					return
+				}
+
+				response.Body.Close()
+
+				if response.StatusCode != http.StatusOK {
+					t.Errorf("Expected status code %d, got %d", http.StatusOK, response.StatusCode)
+				}
+			}
+		}()
+	}
+
+	requests.Wait()
+	close(done)
+	refreshers.Wait()
+}
+
+// countingAuthUtilsLib counts logins and refreshes and can be set to reject refresh tokens
+type countingAuthUtilsLib struct {
+	logins        atomic.Int64
+	refreshes     atomic.Int64
+	rejectRefresh bool
+}
+
+// Authenticate mocks implementation of authutil's library
+func (m *countingAuthUtilsLib) Authenticate() (*authutils.OAUTHResponse, error) {
+	n := m.logins.Add(1)
+
+	return &authutils.OAUTHResponse{
+		AccessToken:  fmt.Sprintf("login-%d", n),
+		RefreshToken: fmt.Sprintf("login-refresh-%d", n),
+	}, nil
+}
+
+// RefreshToken mocks implementation of authutil's library
+func (m *countingAuthUtilsLib) RefreshToken(ctx context.Context, refreshToken string) (*authutils.OAUTHResponse, error) {
+	n := m.refreshes.Add(1)
+
+	if m.rejectRefresh {
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+
+	return &authutils.OAUTHResponse{
+		AccessToken:  fmt.Sprintf("refreshed-%d", n),
+		RefreshToken: fmt.Sprintf("refreshed-refresh-%d", n),
+	}, nil
+}
+
+func TestMakeRequest_ReauthenticatesOnUnauthorized(t *testing.T) {
+	type want struct {
+		logins    int64
+		refreshes int64
+		token     string
+	}
+
+	tests := []struct {
+		name          string
+		rejectRefresh bool
+		want          want
+	}{
+		{
+			name:          "Happy case: refresh and replay",
+			rejectRefresh: false,
+			want: want{
+				logins:    1,
+				refreshes: 1,
+				token:     "Bearer refreshed-1",
+			},
+		},
+		{
+			name:          "Happy case: refresh token rejected, login and replay",
+			rejectRefresh: true,
+			want: want{
+				logins:    2,
+				refreshes: 1,
+				token:     "Bearer login-2",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			var replayedToken, replayedBody string
+
+			path := "https://healthcrm.test/v1/facilities/facilities/"
+			httpmock.RegisterResponder(http.MethodPost, path, func(req *http.Request) (*http.Response, error) {
+				if req.Header.Get("Authorization") == "Bearer login-1" {
+					return httpmock.NewStringResponse(http.StatusUnauthorized, `{"detail": "token revoked"}`), nil
+				}
+
+				body, err := io.ReadAll(req.Body)
+				if err != nil {
+					return nil, err
+				}
+
+				replayedToken = req.Header.Get("Authorization")
+				replayedBody = string(body)
+
+				return httpmock.NewStringResponse(http.StatusCreated, "{}"), nil
+			})
+
+			authClient := &countingAuthUtilsLib{rejectRefresh: tt.rejectRefresh}
+			mockClient := newTestClient(t, authClient)
+
+			response, err := mockClient.MakeRequest(context.Background(), http.MethodPost, "/v1/facilities/facilities/", nil, &Facility{Name: "Test Facility"})
+			if err != nil {
+				t.Fatalf("Error making request: %v", err)
+			}
+
+			defer response.Body.Close()
+
+			if response.StatusCode != http.StatusCreated {
+				t.Errorf("Expected status code %d, got %d", http.StatusCreated, response.StatusCode)
+			}
+
+			if replayedToken != tt.want.token {
+				t.Errorf("Expected replay with %q, got %q", tt.want.token, replayedToken)
+			}
+
+			if replayedBody != `{"name":"Test Facility"}` {
+				t.Errorf("Expected the original body to be replayed, got %q", replayedBody)
+			}
+
+			if got := authClient.logins.Load(); got != tt.want.logins {
+				t.Errorf("Expected %d logins, got %d", tt.want.logins, got)
+			}
+
+			if got := authClient.refreshes.Load(); got != tt.want.refreshes {
+				t.Errorf("Expected %d refreshes, got %d", tt.want.refreshes, got)
+			}
+		})
+	}
+}
+
+func TestMakeRequest_ConcurrentUnauthorizedSharesRefresh(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	path := "https://healthcrm.test/v1/facilities/facilities/"
+	httpmock.RegisterResponder(http.MethodGet, path, func(req *http.Request) (*http.Response, error) {
+		if req.Header.Get("Authorization") == "Bearer login-1" {
+			return httpmock.NewStringResponse(http.StatusUnauthorized, ""), nil
+		}
+
+		return httpmock.NewStringResponse(http.StatusOK, "{}"), nil
+	})
+
+	authClient := &countingAuthUtilsLib{}
+	mockClient := newTestClient(t, authClient)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			response, err := mockClient.MakeRequest(context.Background(), http.MethodGet, "/v1/facilities/facilities/", nil, nil)
+			if err != nil {
+				t.Errorf("Error making request: %v", err)
+				return
+			}
+
+			response.Body.Close()
+
+			if response.StatusCode != http.StatusOK {
+				t.Errorf("Expected status code %d, got %d", http.StatusOK, response.StatusCode)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if got := authClient.refreshes.Load(); got != 1 {
+		t.Errorf("Expected a single shared refresh, got %d", got)
+	}
+}
+
+func TestMakeRequest_UnauthorizedAfterReplay(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	calls := 0
+	path := "https://healthcrm.test/v1/facilities/facilities/"
+	httpmock.RegisterResponder(http.MethodGet, path, func(req *http.Request) (*http.Response, error) {
+		calls++
+		return httpmock.NewStringResponse(http.StatusUnauthorized, ""), nil
+	})
+
+	mockClient := newTestClient(t, &countingAuthUtilsLib{})
+
+	response, err := mockClient.MakeRequest(context.Background(), http.MethodGet, "/v1/facilities/facilities/", nil, nil)
+	if err != nil {
+		t.Fatalf("Error making request: %v", err)
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnauthorized, response.StatusCode)
+	}
+
+	if calls != 2 {
+		t.Errorf("Expected the request to be replayed once, got %d calls", calls)
+	}
+}
+
+func TestRefreshInterval(t *testing.T) {
+	type args struct {
+		expiresIn time.Duration
+		margin    time.Duration
+		jitter    time.Duration
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{
+			name:    "Happy case: one hour token with the default margin",
+			args:    args{expiresIn: time.Hour, margin: time.Minute},
+			wantMin: 59 * time.Minute,
+			wantMax: 59 * time.Minute,
+		},
+		{
+			name:    "Happy case: ten minute token with jitter",
+			args:    args{expiresIn: 10 * time.Minute, margin: time.Minute, jitter: 30 * time.Second},
+			wantMin: 8*time.Minute + 30*time.Second,
+			wantMax: 9 * time.Minute,
+		},
+		{
+			name:    "Happy case: margin longer than the token's lifetime",
+			args:    args{expiresIn: 30 * time.Second, margin: time.Minute},
+			wantMin: 15 * time.Second,
+			wantMax: 15 * time.Second,
+		},
+		{
+			name:    "Happy case: very short lived token",
+			args:    args{expiresIn: time.Second, margin: time.Minute},
+			wantMin: time.Second,
+			wantMax: time.Second,
+		},
+		{
+			name:    "Happy case: expires_in not provided",
+			args:    args{expiresIn: 0, margin: time.Minute},
+			wantMin: accessTokenTimeout,
+			wantMax: accessTokenTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := refreshInterval(tt.args.expiresIn, tt.args.margin, tt.args.jitter)
+			if got < tt.wantMin || got > tt.wantMax {
+				t.Errorf("refreshInterval() = %v, want between %v and %v", got, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestSetToken_TokenExpiry(t *testing.T) {
+	mockClient := &client{
+		refreshMargin: time.Minute,
+	}
+
+	expiry := time.Now().Add(10 * time.Minute)
+	mockClient.setToken(&Token{
+		AccessToken: "access",
+		Expiry:      expiry,
+	})
+
+	if !mockClient.getTokenExpiry().Equal(expiry) {
+		t.Errorf("expected the token to expire at %v, got %v", expiry, mockClient.getTokenExpiry())
+	}
+
+	mockClient.setToken(&Token{
+		AccessToken: "access",
+	})
+
+	if !mockClient.getTokenExpiry().IsZero() {
+		t.Errorf("expected a zero expiry when the token's expiry is unknown, got %v", mockClient.getTokenExpiry())
+	}
+
+	mockClient.accessTokenTicker.Stop()
+}
+
+func TestMakeMultipartRequest(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var contentTypes, bodies []string
+
+	path := "https://healthcrm.test/v1/facilities/facilities/123/facility_images/"
+	httpmock.RegisterResponder(http.MethodPost, path, func(req *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		contentTypes = append(contentTypes, req.Header.Get("Content-Type"))
+		bodies = append(bodies, string(body))
+
+		if req.Header.Get("Authorization") == "Bearer login-1" {
+			return httpmock.NewStringResponse(http.StatusUnauthorized, `{"detail": "token revoked"}`), nil
+		}
+
+		return httpmock.NewStringResponse(http.StatusCreated, "{}"), nil
+	})
+
+	mockClient := newTestClient(t, &countingAuthUtilsLib{})
+
+	contentType := "multipart/form-data; boundary=test"
+	form := "--test\r\nContent-Disposition: form-data; name=\"title\"\r\n\r\nEntrance\r\n--test--\r\n"
+
+	response, err := mockClient.MakeMultipartRequest(context.Background(), http.MethodPost, "/v1/facilities/facilities/123/facility_images/", []byte(form), contentType)
+	if err != nil {
+		t.Fatalf("Error making request: %v", err)
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusCreated {
+		t.Errorf("Expected status code %d, got %d", http.StatusCreated, response.StatusCode)
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("Expected the request to be replayed once, got %d calls", len(bodies))
+	}
+
+	for i := range bodies {
+		if contentTypes[i] != contentType || bodies[i] != form {
+			t.Errorf("Expected call %d to send the form as %q, got %q as %q", i+1, contentType, bodies[i], contentTypes[i])
+		}
+	}
+
+	if _, err := mockClient.MakeMultipartRequest(context.Background(), http.MethodGet, "/v1/facilities/facilities/123/facility_images/", []byte(form), contentType); err == nil {
+		t.Errorf("Expected an error for a multipart GET request")
+	}
+}
@@ -0,0 +1,182 @@
+package healthcrm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"regexp"
+)
+
+// phoneNumberPattern matches phone numbers in local (0712345678) or international (+254712345678) format
+var phoneNumberPattern = regexp.MustCompile(`^\+?[0-9]{7,15}$`)
+
+// FacilityContactInput is used to add or update a facility's contact
+type FacilityContactInput struct {
+	ContactType  ContactType `json:"contact_type"`
+	ContactValue string      `json:"contact_value"`
+	Role         string      `json:"role,omitempty"`
+}
+
+// Validate checks that the contact type is known and that the value is a valid phone number or email address
+func (c FacilityContactInput) Validate() error {
+	if !c.ContactType.IsValid() {
+		return fmt.Errorf("invalid contact type: %s", c.ContactType)
+	}
+
+	if c.ContactValue == "" {
+		return errors.New("contact value must be provided")
+	}
+
+	switch c.ContactType {
+	case ContactTypePhoneNumber:
+		if !phoneNumberPattern.MatchString(c.ContactValue) {
+			return fmt.Errorf("invalid phone number: %s", c.ContactValue)
+		}
+
+	case ContactTypeEmail:
+		address, err := mail.ParseAddress(c.ContactValue)
+		if err != nil || address.Address != c.ContactValue {
+			return fmt.Errorf("invalid email address: %s", c.ContactValue)
+		}
+	}
+
+	return nil
+}
+
+// facilityContactStatusInput is used to activate or deactivate a facility's contact
+type facilityContactStatusInput struct {
+	Active bool `json:"active"`
+}
+
+// facilityContactsPath returns the path of a facility's contacts
+func facilityContactsPath(facilityID string) string {
+	return fmt.Sprintf("/v1/facilities/facilities/%s/contacts/", facilityID)
+}
+
+// facilityContactPath returns the path of one of a facility's contacts
+func facilityContactPath(facilityID, contactID string) string {
+	return fmt.Sprintf("/v1/facilities/facilities/%s/contacts/%s/", facilityID, contactID)
+}
+
+// ListFacilityContacts fetches all the contacts of a facility, following every page of the listing
+func (h *HealthCRMLib) ListFacilityContacts(ctx context.Context, facilityID string) ([]ContactsOutput, error) {
+	if facilityID == "" {
+		return nil, errors.New("facility ID must be provided")
+	}
+
+	return collect(paginate(ctx, func(ctx context.Context, pagination *Pagination) (*Page[ContactsOutput], error) {
+		return h.getFacilityContacts(ctx, facilityID, pagination)
+	}, nil))
+}
+
+// getFacilityContacts fetches one page of a facility's contacts
+func (h *HealthCRMLib) getFacilityContacts(ctx context.Context, facilityID string, pagination *Pagination) (*Page[ContactsOutput], error) {
+	queryParams := url.Values{}
+	if err := pagination.addTo(queryParams); err != nil {
+		return nil, err
+	}
+
+	response, err := h.client.MakeRequest(ctx, http.MethodGet, facilityContactsPath(facilityID), queryParams, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	respBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read response: %w", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, newResponseError(response, respBytes)
+	}
+
+	var contacts *Page[ContactsOutput]
+
+	err = json.Unmarshal(respBytes, &contacts)
+	if err != nil {
+		return nil, err
+	}
+
+	return contacts, nil
+}
+
+// AddFacilityContact adds a contact e.g a hotline to a facility without re-sending the whole facility record
+func (h *HealthCRMLib) AddFacilityContact(ctx context.Context, facilityID string, input FacilityContactInput) (*ContactsOutput, error) {
+	if facilityID == "" {
+		return nil, errors.New("facility ID must be provided")
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	response, err := h.client.MakeRequest(ctx, http.MethodPost, facilityContactsPath(facilityID), nil, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return readFacilityContact(response, http.StatusCreated)
+}
+
+// UpdateFacilityContact changes the type, value or role of one of a facility's contacts
+func (h *HealthCRMLib) UpdateFacilityContact(ctx context.Context, facilityID, contactID string, input FacilityContactInput) (*ContactsOutput, error) {
+	if facilityID == "" || contactID == "" {
+		return nil, errors.New("facility ID and contact ID must be provided")
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	response, err := h.client.MakeRequest(ctx, http.MethodPatch, facilityContactPath(facilityID, contactID), nil, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return readFacilityContact(response, http.StatusOK)
+}
+
+// DeactivateFacilityContact marks one of a facility's contacts as no longer in use.
+// The contact is kept in health CRM with Active set to false.
+func (h *HealthCRMLib) DeactivateFacilityContact(ctx context.Context, facilityID, contactID string) (*ContactsOutput, error) {
+	if facilityID == "" || contactID == "" {
+		return nil, errors.New("facility ID and contact ID must be provided")
+	}
+
+	response, err := h.client.MakeRequest(ctx, http.MethodPatch, facilityContactPath(facilityID, contactID), nil, facilityContactStatusInput{Active: false})
+	if err != nil {
+		return nil, err
+	}
+
+	return readFacilityContact(response, http.StatusOK)
+}
+
+// readFacilityContact reads the contact in a response that is expected to have the given status code
+func readFacilityContact(response *http.Response, expectedStatusCode int) (*ContactsOutput, error) {
+	defer response.Body.Close()
+
+	respBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read response: %w", err)
+	}
+
+	if response.StatusCode != expectedStatusCode {
+		return nil, newResponseError(response, respBytes)
+	}
+
+	var contact *ContactsOutput
+
+	err = json.Unmarshal(respBytes, &contact)
+	if err != nil {
+		return nil, err
+	}
+
+	return contact, nil
+}
@@ -0,0 +1,256 @@
+package healthcrm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestFacilityContactInput_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   FacilityContactInput
+		wantErr bool
+	}{
+		{
+			name:  "Happy case: local phone number",
+			input: FacilityContactInput{ContactType: ContactTypePhoneNumber, ContactValue: "0712345678"},
+		},
+		{
+			name:  "Happy case: international phone number",
+			input: FacilityContactInput{ContactType: ContactTypePhoneNumber, ContactValue: "+254712345678", Role: "HOTLINE"},
+		},
+		{
+			name:  "Happy case: email",
+			input: FacilityContactInput{ContactType: ContactTypeEmail, ContactValue: "info@hospital.co.ke"},
+		},
+		{
+			name:    "Sad case: invalid contact type",
+			input:   FacilityContactInput{ContactType: ContactType("FAX"), ContactValue: "0712345678"},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: missing value",
+			input:   FacilityContactInput{ContactType: ContactTypePhoneNumber},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: phone number with letters",
+			input:   FacilityContactInput{ContactType: ContactTypePhoneNumber, ContactValue: "07123abc78"},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: phone number too short",
+			input:   FacilityContactInput{ContactType: ContactTypePhoneNumber, ContactValue: "07123"},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: invalid email",
+			input:   FacilityContactInput{ContactType: ContactTypeEmail, ContactValue: "info at hospital"},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: email with a display name",
+			input:   FacilityContactInput{ContactType: ContactTypeEmail, ContactValue: "Info <info@hospital.co.ke>"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.input.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("FacilityContactInput.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHealthCRMLib_FacilityContacts(t *testing.T) {
+	ctx := context.Background()
+	hotline := FacilityContactInput{ContactType: ContactTypePhoneNumber, ContactValue: "+254712345678", Role: "HOTLINE"}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		statusCode int
+		call       func(h *HealthCRMLib) (*ContactsOutput, error)
+		wantBody   map[string]any
+		wantErr    bool
+	}{
+		{
+			name:       "Happy case: add contact",
+			method:     http.MethodPost,
+			path:       "/v1/facilities/facilities/123/contacts/",
+			statusCode: http.StatusCreated,
+			call: func(h *HealthCRMLib) (*ContactsOutput, error) {
+				return h.AddFacilityContact(ctx, "123", hotline)
+			},
+			wantBody: map[string]any{"contact_type": "PHONE_NUMBER", "contact_value": "+254712345678", "role": "HOTLINE"},
+		},
+		{
+			name:       "Happy case: update contact",
+			method:     http.MethodPatch,
+			path:       "/v1/facilities/facilities/123/contacts/456/",
+			statusCode: http.StatusOK,
+			call: func(h *HealthCRMLib) (*ContactsOutput, error) {
+				return h.UpdateFacilityContact(ctx, "123", "456", hotline)
+			},
+			wantBody: map[string]any{"contact_type": "PHONE_NUMBER", "contact_value": "+254712345678", "role": "HOTLINE"},
+		},
+		{
+			name:       "Happy case: deactivate contact",
+			method:     http.MethodPatch,
+			path:       "/v1/facilities/facilities/123/contacts/456/",
+			statusCode: http.StatusOK,
+			call: func(h *HealthCRMLib) (*ContactsOutput, error) {
+				return h.DeactivateFacilityContact(ctx, "123", "456")
+			},
+			wantBody: map[string]any{"active": false},
+		},
+		{
+			name:       "Sad case: add contact rejected",
+			method:     http.MethodPost,
+			path:       "/v1/facilities/facilities/123/contacts/",
+			statusCode: http.StatusBadRequest,
+			call: func(h *HealthCRMLib) (*ContactsOutput, error) {
+				return h.AddFacilityContact(ctx, "123", hotline)
+			},
+			wantErr: true,
+		},
+		{
+			name:       "Sad case: update contact not found",
+			method:     http.MethodPatch,
+			path:       "/v1/facilities/facilities/123/contacts/456/",
+			statusCode: http.StatusNotFound,
+			call: func(h *HealthCRMLib) (*ContactsOutput, error) {
+				return h.UpdateFacilityContact(ctx, "123", "456", hotline)
+			},
+			wantErr: true,
+		},
+		{
+			name:   "Sad case: invalid phone number is not sent",
+			method: http.MethodPost,
+			path:   "/v1/facilities/facilities/123/contacts/",
+			call: func(h *HealthCRMLib) (*ContactsOutput, error) {
+				return h.AddFacilityContact(ctx, "123", FacilityContactInput{ContactType: ContactTypePhoneNumber, ContactValue: "call us"})
+			},
+			wantErr: true,
+		},
+		{
+			name:   "Sad case: missing contact ID",
+			method: http.MethodPatch,
+			path:   "/v1/facilities/facilities/123/contacts//",
+			call: func(h *HealthCRMLib) (*ContactsOutput, error) {
+				return h.DeactivateFacilityContact(ctx, "123", "")
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			MockAuthenticate()
+
+			var sent map[string]any
+
+			url := fmt.Sprintf("%s%s", baseURL, tt.path)
+			httpmock.RegisterResponder(tt.method, url, func(r *http.Request) (*http.Response, error) {
+				if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+					return nil, err
+				}
+
+				if tt.statusCode != http.StatusOK && tt.statusCode != http.StatusCreated {
+					return httpmock.NewStringResponse(tt.statusCode, `{"contact_value": ["Enter a valid phone number."]}`), nil
+				}
+
+				return httpmock.NewJsonResponse(tt.statusCode, &ContactsOutput{ID: "456", ContactType: "PHONE_NUMBER", ContactValue: "+254712345678", FacilityID: "123"})
+			})
+
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
+			if err != nil {
+				t.Fatalf("unable to initialize sdk: %v", err)
+			}
+			defer h.Close()
+
+			contact, err := tt.call(h)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.statusCode == 0 {
+				if count := httpmock.GetCallCountInfo()[tt.method+" "+url]; count != 0 {
+					t.Errorf("invalid input sent %d requests, want none", count)
+				}
+
+				return
+			}
+
+			for key, value := range tt.wantBody {
+				if sent[key] != value {
+					t.Errorf("request body %s = %v, want %v", key, sent[key], value)
+				}
+			}
+
+			if !tt.wantErr && contact.ID != "456" {
+				t.Errorf("contact = %+v, want the contact returned by health CRM", contact)
+			}
+		})
+	}
+}
+
+func TestHealthCRMLib_ListFacilityContacts(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	MockAuthenticate()
+
+	path := fmt.Sprintf("%s/v1/facilities/facilities/123/contacts/", baseURL)
+	httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
+		if r.URL.Query().Get("page") == "2" {
+			return httpmock.NewStringResponse(http.StatusOK, `{
+				"count": 2,
+				"next": null,
+				"previous": "page=1",
+				"results": [
+					{"id": "2", "contact_type": "EMAIL", "contact_value": "info@hospital.co.ke", "active": false, "facility_id": "123"}
+				]
+			}`), nil
+		}
+
+		return httpmock.NewStringResponse(http.StatusOK, `{
+			"count": 2,
+			"next": "page=2",
+			"previous": null,
+			"results": [
+				{"id": "1", "contact_type": "PHONE_NUMBER", "contact_value": "0712345678", "active": true, "facility_id": "123"}
+			]
+		}`), nil
+	})
+
+	h, err := NewHealthCRMLib(testOptions(baseURL)...)
+	if err != nil {
+		t.Fatalf("unable to initialize sdk: %v", err)
+	}
+	defer h.Close()
+
+	contacts, err := h.ListFacilityContacts(context.Background(), "123")
+	if err != nil {
+		t.Fatalf("HealthCRMLib.ListFacilityContacts() error = %v", err)
+	}
+
+	if len(contacts) != 2 || !contacts[0].Active || contacts[1].Active {
+		t.Errorf("HealthCRMLib.ListFacilityContacts() = %+v, want the contacts on both pages with their active flags", contacts)
+	}
+
+	httpmock.RegisterResponder(http.MethodGet, path, httpmock.NewStringResponder(http.StatusNotFound, `{"detail": "Not found."}`))
+
+	_, err = h.ListFacilityContacts(context.Background(), "123")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("HealthCRMLib.ListFacilityContacts() error = %v, want %v", err, ErrNotFound)
+	}
+}
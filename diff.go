@@ -0,0 +1,299 @@
+package healthcrm
+
+import (
+	"strings"
+)
+
+// FieldChange is a facility field whose local value differs from health CRM's
+type FieldChange struct {
+	// Field is the field's JSON name e.g facility_type
+	Field  string
+	Local  string
+	Remote string
+}
+
+// FacilityChangeSet lists how a local facility differs from its record in health CRM.
+//
+// Contacts, identifiers and business hours are matched by value since local records have no health CRM IDs.
+// The Added lists hold local entries missing in health CRM and the Removed lists hold health CRM entries
+// missing locally. Inactive contacts are ignored.
+type FacilityChangeSet struct {
+	Fields               []FieldChange
+	AddedContacts        []Contacts
+	RemovedContacts      []ContactsOutput
+	AddedIdentifiers     []Identifiers
+	RemovedIdentifiers   []IdentifiersOutput
+	AddedBusinessHours   []BusinessHours
+	RemovedBusinessHours []BusinessHoursOutput
+}
+
+// IsEmpty reports whether the local facility matches health CRM
+func (c *FacilityChangeSet) IsEmpty() bool {
+	return len(c.Fields) == 0 && !c.ContactsChanged() && !c.IdentifiersChanged() && !c.BusinessHoursChanged()
+}
+
+// ContactsChanged reports whether any contact was added or removed
+func (c *FacilityChangeSet) ContactsChanged() bool {
+	return len(c.AddedContacts) > 0 || len(c.RemovedContacts) > 0
+}
+
+// IdentifiersChanged reports whether any identifier was added or removed
+func (c *FacilityChangeSet) IdentifiersChanged() bool {
+	return len(c.AddedIdentifiers) > 0 || len(c.RemovedIdentifiers) > 0
+}
+
+// BusinessHoursChanged reports whether any business hours were added or removed
+func (c *FacilityChangeSet) BusinessHoursChanged() bool {
+	return len(c.AddedBusinessHours) > 0 || len(c.RemovedBusinessHours) > 0
+}
+
+// DiffFacility compares a local facility with its record in health CRM e.g from GetFacilityByID.
+//
+// Empty local fields and nil local lists are treated as not managed locally and are not compared.
+// A non-nil empty list means the facility should have none, so every entry in health CRM is reported as removed.
+func DiffFacility(local Facility, remote FacilityOutput) *FacilityChangeSet {
+	changes := &FacilityChangeSet{}
+
+	fields := []FieldChange{
+		{Field: "name", Local: local.Name, Remote: remote.Name},
+		{Field: "description", Local: local.Description, Remote: remote.Description},
+		{Field: "facility_type", Local: local.FacilityType, Remote: remote.FacilityType},
+		{Field: "county", Local: local.County, Remote: remote.County},
+		{Field: "country", Local: local.Country, Remote: remote.Country},
+		{Field: "address", Local: local.Address, Remote: remote.Address},
+	}
+
+	for _, field := range fields {
+		if field.Local != "" && field.Local != field.Remote {
+			changes.Fields = append(changes.Fields, field)
+		}
+	}
+
+	if change, ok := diffCoordinates(local.Coordinates, remote.Coordinates); ok {
+		changes.Fields = append(changes.Fields, change)
+	}
+
+	if local.Contacts != nil {
+		changes.AddedContacts, changes.RemovedContacts = diffContacts(local.Contacts, remote.Contacts)
+	}
+
+	if local.Identifiers != nil {
+		changes.AddedIdentifiers, changes.RemovedIdentifiers = diffIdentifiers(local.Identifiers, remote.Identifiers)
+	}
+
+	if local.BusinessHours != nil {
+		changes.AddedBusinessHours, changes.RemovedBusinessHours = diffBusinessHours(local.BusinessHours, remote.BusinessHours)
+	}
+
+	return changes
+}
+
+// ReconcileFacility builds the smallest UpdateFacility payload that brings health CRM in line with the local facility.
+// Changed fields are set on their own. Health CRM replaces nested lists as a whole, so a list with any change is sent in full.
+// An emptied list is sent as [] so that health CRM removes every item. The payload is nil when nothing has changed.
+func ReconcileFacility(local Facility, remote FacilityOutput) (*Facility, *FacilityChangeSet) {
+	changes := DiffFacility(local, remote)
+	if changes.IsEmpty() {
+		return nil, changes
+	}
+
+	patch := &Facility{}
+
+	for _, field := range changes.Fields {
+		switch field.Field {
+		case "name":
+			patch.Name = local.Name
+		case "description":
+			patch.Description = local.Description
+		case "facility_type":
+			patch.FacilityType = local.FacilityType
+		case "county":
+			patch.County = local.County
+		case "country":
+			patch.Country = local.Country
+		case "address":
+			patch.Address = local.Address
+		case "coordinates":
+			patch.Coordinates = local.Coordinates
+		}
+	}
+
+	if changes.ContactsChanged() {
+		patch.Contacts = local.Contacts
+	}
+
+	if changes.IdentifiersChanged() {
+		patch.Identifiers = local.Identifiers
+	}
+
+	if changes.BusinessHoursChanged() {
+		patch.BusinessHours = local.BusinessHours
+	}
+
+	return patch, changes
+}
+
+// diffCoordinates compares the local coordinates with health CRM's to 5 decimal places.
+// Local coordinates that cannot be read are reported as a change so that health CRM can reject them.
+func diffCoordinates(local *Coordinates, remote CoordinatesOutput) (FieldChange, bool) {
+	if local == nil || (local.Latitude == "" && local.Longitude == "") {
+		return FieldChange{}, false
+	}
+
+	remoteValue := ""
+	if remote != (CoordinatesOutput{}) {
+		remoteValue = remote.GeoPoint().String()
+	}
+
+	point, err := local.GeoPoint()
+	if err != nil {
+		return FieldChange{Field: "coordinates", Local: local.Latitude + ", " + local.Longitude, Remote: remoteValue}, true
+	}
+
+	if remoteValue != "" && samePoint(point, remote.GeoPoint()) {
+		return FieldChange{}, false
+	}
+
+	return FieldChange{Field: "coordinates", Local: point.String(), Remote: remoteValue}, true
+}
+
+// contactKey identifies a contact by its type, value and role
+func contactKey(contactType, value, role string) string {
+	return strings.ToUpper(strings.TrimSpace(contactType)) + "|" + strings.TrimSpace(value) + "|" + strings.ToUpper(strings.TrimSpace(role))
+}
+
+// diffContacts returns the local contacts missing in health CRM and health CRM's active contacts missing locally
+func diffContacts(local []Contacts, remote []ContactsOutput) ([]Contacts, []ContactsOutput) {
+	remoteKeys := map[string]bool{}
+
+	for _, contact := range remote {
+		if contact.Active {
+			remoteKeys[contactKey(contact.ContactType, contact.ContactValue, contact.Role)] = true
+		}
+	}
+
+	localKeys := map[string]bool{}
+
+	var added []Contacts
+
+	for _, contact := range local {
+		key := contactKey(contact.ContactType, contact.ContactValue, contact.Role)
+		localKeys[key] = true
+
+		if !remoteKeys[key] {
+			added = append(added, contact)
+		}
+	}
+
+	var removed []ContactsOutput
+
+	for _, contact := range remote {
+		if contact.Active && !localKeys[contactKey(contact.ContactType, contact.ContactValue, contact.Role)] {
+			removed = append(removed, contact)
+		}
+	}
+
+	return added, removed
+}
+
+// identifierMatches reports whether a local identifier is the same as one in health CRM.
+// Validity dates are only compared when they are set locally since health CRM fills them in.
+func identifierMatches(local Identifiers, remote IdentifiersOutput) bool {
+	if !strings.EqualFold(local.IdentifierType, remote.IdentifierType) || local.IdentifierValue != remote.IdentifierValue {
+		return false
+	}
+
+	if local.ValidFrom != "" && !sameDate(local.ValidFrom, remote.ValidFrom) {
+		return false
+	}
+
+	return local.ValidTo == "" || sameDate(local.ValidTo, remote.ValidTo)
+}
+
+// sameDate compares the date part of two dates or timestamps e.g 2024-07-01 and 2024-07-01T00:00:00Z
+func sameDate(a, b string) bool {
+	if len(a) > len(identifierDateLayout) {
+		a = a[:len(identifierDateLayout)]
+	}
+
+	if len(b) > len(identifierDateLayout) {
+		b = b[:len(identifierDateLayout)]
+	}
+
+	return a == b
+}
+
+// diffIdentifiers returns the local identifiers missing in health CRM and health CRM's identifiers missing locally
+func diffIdentifiers(local []Identifiers, remote []IdentifiersOutput) ([]Identifiers, []IdentifiersOutput) {
+	var added []Identifiers
+
+	matched := make([]bool, len(remote))
+
+	for _, identifier := range local {
+		found := false
+
+		for i, remoteIdentifier := range remote {
+			if !matched[i] && identifierMatches(identifier, remoteIdentifier) {
+				matched[i], found = true, true
+				break
+			}
+		}
+
+		if !found {
+			added = append(added, identifier)
+		}
+	}
+
+	var removed []IdentifiersOutput
+
+	for i, identifier := range remote {
+		if !matched[i] {
+			removed = append(removed, identifier)
+		}
+	}
+
+	return added, removed
+}
+
+// businessHoursKey identifies business hours by their day and times, so that 08:00 matches 08:00:00.
+// Hours that cannot be read are compared as they are.
+func businessHoursKey(day, openingTime, closingTime string) string {
+	period, err := ParseOpeningPeriod(day, openingTime, closingTime)
+	if err != nil {
+		return day + "|" + openingTime + "|" + closingTime
+	}
+
+	return period.Day.String() + "|" + period.Opens.String() + "|" + period.Closes.String()
+}
+
+// diffBusinessHours returns the local business hours missing in health CRM and health CRM's business hours missing locally
+func diffBusinessHours(local []BusinessHours, remote []BusinessHoursOutput) ([]BusinessHours, []BusinessHoursOutput) {
+	remoteKeys := map[string]bool{}
+
+	for _, hours := range remote {
+		remoteKeys[businessHoursKey(hours.Day, hours.OpeningTime, hours.ClosingTime)] = true
+	}
+
+	localKeys := map[string]bool{}
+
+	var added []BusinessHours
+
+	for _, hours := range local {
+		key := businessHoursKey(hours.Day, hours.OpeningTime, hours.ClosingTime)
+		localKeys[key] = true
+
+		if !remoteKeys[key] {
+			added = append(added, hours)
+		}
+	}
+
+	var removed []BusinessHoursOutput
+
+	for _, hours := range remote {
+		if !localKeys[businessHoursKey(hours.Day, hours.OpeningTime, hours.ClosingTime)] {
+			removed = append(removed, hours)
+		}
+	}
+
+	return added, removed
+}
@@ -0,0 +1,213 @@
+package healthcrm
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// remoteFacility is a facility as health CRM returns it
+var remoteFacility = FacilityOutput{
+	ID:           "123",
+	Name:         "Mbagathi",
+	FacilityType: "HOSPITAL",
+	County:       "Nairobi",
+	Country:      "KE",
+	Coordinates:  CoordinatesOutput{Latitude: -1.31, Longitude: 36.8},
+	Contacts: []ContactsOutput{
+		{ID: "c1", ContactType: "PHONE_NUMBER", ContactValue: "+254712345678", Role: "PRIMARY", Active: true},
+		{ID: "c2", ContactType: "EMAIL", ContactValue: "old@example.com", Active: false},
+	},
+	Identifiers: []IdentifiersOutput{
+		{ID: "i1", IdentifierType: "MFL_CODE", IdentifierValue: "12345", ValidFrom: "2024-01-01"},
+		{ID: "i2", IdentifierType: "SHA_SLADE_CODE", IdentifierValue: "SHA-1", ValidFrom: "2024-01-01T00:00:00Z"},
+	},
+	BusinessHours: []BusinessHoursOutput{
+		{ID: "b1", Day: "MONDAY", OpeningTime: "08:00:00", ClosingTime: "17:00:00"},
+		{ID: "b2", Day: "SATURDAY", OpeningTime: "09:00:00", ClosingTime: "13:00:00"},
+	},
+}
+
+func TestDiffFacility(t *testing.T) {
+	tests := []struct {
+		name                 string
+		local                Facility
+		wantFields           []FieldChange
+		wantAddedContacts    int
+		wantRemovedContacts  int
+		wantAddedIDs         int
+		wantRemovedIDs       int
+		wantAddedHours       int
+		wantRemovedHours     int
+		wantEmpty            bool
+		wantRemovedContactID string
+	}{
+		{
+			name: "Happy case: matching facility",
+			local: Facility{
+				Name:         "Mbagathi",
+				FacilityType: "HOSPITAL",
+				Coordinates:  &Coordinates{Latitude: "-1.310000", Longitude: "36.80"},
+				Contacts:     []Contacts{{ContactType: "phone_number", ContactValue: "+254712345678", Role: "primary"}},
+				Identifiers: []Identifiers{
+					{IdentifierType: "MFL_CODE", IdentifierValue: "12345"},
+					{IdentifierType: "SHA_SLADE_CODE", IdentifierValue: "SHA-1", ValidFrom: "2024-01-01"},
+				},
+				BusinessHours: []BusinessHours{
+					{Day: "monday", OpeningTime: "08:00", ClosingTime: "17:00"},
+					{Day: "SATURDAY", OpeningTime: "09:00", ClosingTime: "13:00"},
+				},
+			},
+			wantEmpty: true,
+		},
+		{
+			name:      "Happy case: only name managed locally",
+			local:     Facility{Name: "Mbagathi"},
+			wantEmpty: true,
+		},
+		{
+			name: "Happy case: changed fields",
+			local: Facility{
+				Name:        "Mbagathi County Hospital",
+				Address:     "Mbagathi Way",
+				Coordinates: &Coordinates{Latitude: "-1.32", Longitude: "36.8"},
+			},
+			wantFields: []FieldChange{
+				{Field: "name", Local: "Mbagathi County Hospital", Remote: "Mbagathi"},
+				{Field: "address", Local: "Mbagathi Way", Remote: ""},
+				{Field: "coordinates", Local: "-1.32, 36.8", Remote: "-1.31, 36.8"},
+			},
+		},
+		{
+			name: "Happy case: changed nested lists",
+			local: Facility{
+				Contacts: []Contacts{
+					{ContactType: "PHONE_NUMBER", ContactValue: "+254712345678", Role: "HOTLINE"},
+					{ContactType: "EMAIL", ContactValue: "old@example.com"},
+				},
+				Identifiers: []Identifiers{
+					{IdentifierType: "MFL_CODE", IdentifierValue: "12345", ValidFrom: "2024-06-01"},
+				},
+				BusinessHours: []BusinessHours{
+					{Day: "MONDAY", OpeningTime: "08:00", ClosingTime: "17:00"},
+					{Day: "SATURDAY", OpeningTime: "09:00", ClosingTime: "14:00"},
+				},
+			},
+			wantAddedContacts:    2,
+			wantRemovedContacts:  1,
+			wantRemovedContactID: "c1",
+			wantAddedIDs:         1,
+			wantRemovedIDs:       2,
+			wantAddedHours:       1,
+			wantRemovedHours:     1,
+		},
+		{
+			name:                 "Happy case: empty lists remove everything",
+			local:                Facility{Contacts: []Contacts{}, Identifiers: []Identifiers{}, BusinessHours: []BusinessHours{}},
+			wantRemovedContacts:  1,
+			wantRemovedContactID: "c1",
+			wantRemovedIDs:       2,
+			wantRemovedHours:     2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := DiffFacility(tt.local, remoteFacility)
+
+			if changes.IsEmpty() != tt.wantEmpty {
+				t.Errorf("DiffFacility().IsEmpty() = %v, want %v: %+v", changes.IsEmpty(), tt.wantEmpty, changes)
+			}
+
+			if !reflect.DeepEqual(changes.Fields, tt.wantFields) {
+				t.Errorf("DiffFacility().Fields = %+v, want %+v", changes.Fields, tt.wantFields)
+			}
+
+			counts := []struct {
+				name      string
+				got, want int
+			}{
+				{"added contacts", len(changes.AddedContacts), tt.wantAddedContacts},
+				{"removed contacts", len(changes.RemovedContacts), tt.wantRemovedContacts},
+				{"added identifiers", len(changes.AddedIdentifiers), tt.wantAddedIDs},
+				{"removed identifiers", len(changes.RemovedIdentifiers), tt.wantRemovedIDs},
+				{"added business hours", len(changes.AddedBusinessHours), tt.wantAddedHours},
+				{"removed business hours", len(changes.RemovedBusinessHours), tt.wantRemovedHours},
+			}
+
+			for _, count := range counts {
+				if count.got != count.want {
+					t.Errorf("DiffFacility() %s = %d, want %d", count.name, count.got, count.want)
+				}
+			}
+
+			if tt.wantRemovedContactID != "" && changes.RemovedContacts[0].ID != tt.wantRemovedContactID {
+				t.Errorf("DiffFacility() removed contact %s, want %s", changes.RemovedContacts[0].ID, tt.wantRemovedContactID)
+			}
+		})
+	}
+}
+
+func TestReconcileFacility(t *testing.T) {
+	hours := []BusinessHours{
+		{Day: "MONDAY", OpeningTime: "07:00", ClosingTime: "19:00"},
+	}
+	contacts := []Contacts{
+		{ContactType: "PHONE_NUMBER", ContactValue: "+254712345678", Role: "PRIMARY"},
+	}
+
+	tests := []struct {
+		name     string
+		local    Facility
+		want     *Facility
+		wantJSON string
+	}{
+		{
+			name:  "Happy case: nothing to update",
+			local: Facility{Name: "Mbagathi", County: "Nairobi", Contacts: contacts},
+		},
+		{
+			name:     "Happy case: only changed fields and lists are sent",
+			local:    Facility{Name: "Mbagathi", County: "Kiambu", Contacts: contacts, BusinessHours: hours},
+			want:     &Facility{County: "Kiambu", BusinessHours: hours},
+			wantJSON: `{"county":"Kiambu","businesshours":[{"day":"MONDAY","opening_time":"07:00","closing_time":"19:00"}]}`,
+		},
+		{
+			name:     "Happy case: coordinates",
+			local:    Facility{Coordinates: &Coordinates{Latitude: "-1.32", Longitude: "36.8"}},
+			want:     &Facility{Coordinates: &Coordinates{Latitude: "-1.32", Longitude: "36.8"}},
+			wantJSON: `{"coordinates":{"latitude":"-1.32","longitude":"36.8"}}`,
+		},
+		{
+			name:     "Happy case: emptied lists are sent to remove everything",
+			local:    Facility{Contacts: []Contacts{}, Identifiers: []Identifiers{}, BusinessHours: []BusinessHours{}},
+			want:     &Facility{Contacts: []Contacts{}, Identifiers: []Identifiers{}, BusinessHours: []BusinessHours{}},
+			wantJSON: `{"contacts":[],"identifiers":[],"businesshours":[]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changes := ReconcileFacility(tt.local, remoteFacility)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReconcileFacility() = %+v, want %+v", got, tt.want)
+			}
+
+			if changes.IsEmpty() != (tt.want == nil) {
+				t.Errorf("ReconcileFacility() changes = %+v", changes)
+			}
+
+			if tt.want == nil {
+				return
+			}
+
+			payload, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+
+			if string(payload) != tt.wantJSON {
+				t.Errorf("ReconcileFacility() payload = %s, want %s", payload, tt.wantJSON)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"time"
 )
 
 type IdentifierType string
@@ -67,6 +68,28 @@ const (
 	PractitionerStatusPublished PractitionerStatus = "PUBLISHED"
 )
 
+// FacilityStatus is the lifecycle status of a facility in health CRM
+type FacilityStatus string
+
+const (
+	FacilityStatusDraft     FacilityStatus = "DRAFT"
+	FacilityStatusPublished FacilityStatus = "PUBLISHED"
+	FacilityStatusInactive  FacilityStatus = "INACTIVE"
+)
+
+// DayOfWeek is a day on which a facility or practitioner keeps business hours
+type DayOfWeek string
+
+const (
+	DayOfWeekMonday    DayOfWeek = "MONDAY"
+	DayOfWeekTuesday   DayOfWeek = "TUESDAY"
+	DayOfWeekWednesday DayOfWeek = "WEDNESDAY"
+	DayOfWeekThursday  DayOfWeek = "THURSDAY"
+	DayOfWeekFriday    DayOfWeek = "FRIDAY"
+	DayOfWeekSaturday  DayOfWeek = "SATURDAY"
+	DayOfWeekSunday    DayOfWeek = "SUNDAY"
+)
+
 type PractitionerIdentifierType string
 
 const (
@@ -81,6 +104,15 @@ const (
 	PractitionerIdentifierClientRegistryId        PractitionerIdentifierType = "CLIENT_REGISTRY_ID"        //nolint:all
 )
 
+// ServiceIdentifierType is a list of the coding systems that identify a service
+type ServiceIdentifierType string
+
+const (
+	ServiceIdentifierTypeCIEL     ServiceIdentifierType = "CIEL"
+	ServiceIdentifierTypeSNOMEDCT ServiceIdentifierType = "SNOMED_CT"
+	ServiceIdentifierTypeLOINC    ServiceIdentifierType = "LOINC"
+)
+
 // FacilityIdentifierType is a list of all the facility identifier types.
 type FacilityIdentifierType string
 
@@ -235,3 +267,143 @@ func (m MatchResult) IsValid() bool {
 func (m MatchResult) String() string {
 	return string(m)
 }
+
+// IsValid returns true if a facility status is valid
+func (f FacilityStatus) IsValid() bool {
+	switch f {
+	case FacilityStatusDraft, FacilityStatusPublished, FacilityStatusInactive:
+		return true
+	default:
+		return false
+	}
+}
+
+// String converts the facility status enum to a string
+func (f FacilityStatus) String() string {
+	return string(f)
+}
+
+// UnmarshalGQL converts the supplied value to a facility status.
+func (f *FacilityStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*f = FacilityStatus(str)
+	if !f.IsValid() {
+		return fmt.Errorf("%s is not a valid FacilityStatus type", str)
+	}
+
+	return nil
+}
+
+// MarshalGQL writes the facility status to the supplied writer
+func (f FacilityStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(f.String()))
+}
+
+// IsValid returns true if a facility identifier type is valid
+func (f FacilityIdentifierType) IsValid() bool {
+	switch f {
+	case FacilityIdentifierTypeMFLCode, FacilityIdentifierTypeHealthCRM, FacilityIdentifierTypeSladeCode,
+		FacilityIdentifierTypeSHASladeCode, FacilityIdentifierTypeFIDCode, FacilityIdentifierTypeFRCode,
+		FacilityIdentifierTypeKMPDCRegNumber, FacilityIdentifierTypeSladeAdvantageBranchID:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValid returns true if a day of the week is valid
+func (d DayOfWeek) IsValid() bool {
+	switch d {
+	case DayOfWeekMonday, DayOfWeekTuesday, DayOfWeekWednesday, DayOfWeekThursday,
+		DayOfWeekFriday, DayOfWeekSaturday, DayOfWeekSunday:
+		return true
+	default:
+		return false
+	}
+}
+
+// String converts the day of the week enum to a string
+func (d DayOfWeek) String() string {
+	return string(d)
+}
+
+// Weekday converts the day of the week to a time.Weekday. An invalid day is treated as Sunday.
+func (d DayOfWeek) Weekday() time.Weekday {
+	switch d {
+	case DayOfWeekMonday:
+		return time.Monday
+	case DayOfWeekTuesday:
+		return time.Tuesday
+	case DayOfWeekWednesday:
+		return time.Wednesday
+	case DayOfWeekThursday:
+		return time.Thursday
+	case DayOfWeekFriday:
+		return time.Friday
+	case DayOfWeekSaturday:
+		return time.Saturday
+	case DayOfWeekSunday:
+		return time.Sunday
+	default:
+		return time.Sunday
+	}
+}
+
+// UnmarshalGQL converts the supplied value to a day of the week.
+func (d *DayOfWeek) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*d = DayOfWeek(str)
+	if !d.IsValid() {
+		return fmt.Errorf("%s is not a valid DayOfWeek type", str)
+	}
+
+	return nil
+}
+
+// MarshalGQL writes the day of the week to the supplied writer
+func (d DayOfWeek) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(d.String()))
+}
+
+// IsValid returns true if a service identifier type is valid
+func (s ServiceIdentifierType) IsValid() bool {
+	switch s {
+	case ServiceIdentifierTypeCIEL, ServiceIdentifierTypeSNOMEDCT, ServiceIdentifierTypeLOINC:
+		return true
+	default:
+		return false
+	}
+}
+
+// String converts the service identifier type enum to a string
+func (s ServiceIdentifierType) String() string {
+	return string(s)
+}
+
+// UnmarshalGQL converts the supplied value to a service identifier type.
+func (s *ServiceIdentifierType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*s = ServiceIdentifierType(str)
+	if !s.IsValid() {
+		return fmt.Errorf("%s is not a valid ServiceIdentifierType type", str)
+	}
+
+	return nil
+}
+
+// MarshalGQL writes the service identifier type to the supplied writer
+func (s ServiceIdentifierType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(s.String()))
+}
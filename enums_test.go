@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"strconv"
 	"testing"
+	"time"
 )
 
 func TestIdentifierType_String(t *testing.T) {
@@ -421,3 +422,268 @@ func TestMatchResult_IsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestFacilityStatus_String(t *testing.T) {
+	tests := []struct {
+		name string
+		e    FacilityStatus
+		want string
+	}{
+		{
+			name: "happy case: enum to string",
+			e:    FacilityStatusInactive,
+			want: "INACTIVE",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.e.String(); got != tt.want {
+				t.Errorf("FacilityStatus.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFacilityStatus_IsValid(t *testing.T) {
+	tests := []struct {
+		name string
+		e    FacilityStatus
+		want bool
+	}{
+		{
+			name: "valid type",
+			e:    FacilityStatusPublished,
+			want: true,
+		},
+		{
+			name: "invalid type",
+			e:    FacilityStatus("invalid"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.e.IsValid(); got != tt.want {
+				t.Errorf("FacilityStatus.IsValid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFacilityStatus_UnmarshalGQL(t *testing.T) {
+	value := FacilityStatusDraft
+	invalid := FacilityStatus("invalid")
+
+	type args struct {
+		v interface{}
+	}
+
+	tests := []struct {
+		name    string
+		e       *FacilityStatus
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "valid type",
+			e:    &value,
+			args: args{
+				v: "DRAFT",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid type",
+			e:    &invalid,
+			args: args{
+				v: "this is not a valid type",
+			},
+			wantErr: true,
+		},
+		{
+			name: "non string type",
+			e:    &invalid,
+			args: args{
+				v: 1,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.e.UnmarshalGQL(tt.args.v); (err != nil) != tt.wantErr {
+				t.Errorf("FacilityStatus.UnmarshalGQL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFacilityStatus_MarshalGQL(t *testing.T) {
+	w := &bytes.Buffer{}
+
+	tests := []struct {
+		name  string
+		e     FacilityStatus
+		b     *bytes.Buffer
+		wantW string
+	}{
+		{
+			name:  "valid type enums",
+			e:     FacilityStatusInactive,
+			b:     w,
+			wantW: strconv.Quote("INACTIVE"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.e.MarshalGQL(tt.b)
+
+			if gotW := w.String(); gotW != tt.wantW {
+				t.Errorf("FacilityStatus.MarshalGQL() = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
+
+func TestFacilityIdentifierType_IsValid(t *testing.T) {
+	tests := []struct {
+		name string
+		e    FacilityIdentifierType
+		want bool
+	}{
+		{
+			name: "valid type",
+			e:    FacilityIdentifierTypeSHASladeCode,
+			want: true,
+		},
+		{
+			name: "invalid type",
+			e:    FacilityIdentifierType("invalid"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.e.IsValid(); got != tt.want {
+				t.Errorf("FacilityIdentifierType.IsValid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDayOfWeek_IsValid(t *testing.T) {
+	tests := []struct {
+		name string
+		e    DayOfWeek
+		want bool
+	}{
+		{
+			name: "valid type",
+			e:    DayOfWeekMonday,
+			want: true,
+		},
+		{
+			name: "invalid type",
+			e:    DayOfWeek("Monday"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.e.IsValid(); got != tt.want {
+				t.Errorf("DayOfWeek.IsValid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDayOfWeek_Weekday(t *testing.T) {
+	days := []DayOfWeek{
+		DayOfWeekSunday, DayOfWeekMonday, DayOfWeekTuesday, DayOfWeekWednesday,
+		DayOfWeekThursday, DayOfWeekFriday, DayOfWeekSaturday,
+	}
+
+	for i, day := range days {
+		if got := day.Weekday(); got != time.Weekday(i) {
+			t.Errorf("DayOfWeek(%s).Weekday() = %v, want %v", day, got, time.Weekday(i))
+		}
+	}
+}
+
+func TestDayOfWeek_UnmarshalGQL(t *testing.T) {
+	var day DayOfWeek
+
+	if err := day.UnmarshalGQL("FRIDAY"); err != nil || day != DayOfWeekFriday {
+		t.Errorf("DayOfWeek.UnmarshalGQL() = %v, %v, want FRIDAY", day, err)
+	}
+
+	if err := day.UnmarshalGQL("FUNDAY"); err == nil {
+		t.Errorf("DayOfWeek.UnmarshalGQL() expected an error for an invalid day")
+	}
+
+	if err := day.UnmarshalGQL(5); err == nil {
+		t.Errorf("DayOfWeek.UnmarshalGQL() expected an error for a non string value")
+	}
+}
+
+func TestDayOfWeek_MarshalGQL(t *testing.T) {
+	w := &bytes.Buffer{}
+
+	DayOfWeekSunday.MarshalGQL(w)
+
+	if got := w.String(); got != strconv.Quote("SUNDAY") {
+		t.Errorf("DayOfWeek.MarshalGQL() = %v, want %v", got, strconv.Quote("SUNDAY"))
+	}
+}
+
+func TestServiceIdentifierType_IsValid(t *testing.T) {
+	tests := []struct {
+		name string
+		e    ServiceIdentifierType
+		want bool
+	}{
+		{
+			name: "valid type",
+			e:    ServiceIdentifierTypeCIEL,
+			want: true,
+		},
+		{
+			name: "invalid type",
+			e:    ServiceIdentifierType("ICD"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.e.IsValid(); got != tt.want {
+				t.Errorf("ServiceIdentifierType.IsValid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceIdentifierType_UnmarshalGQL(t *testing.T) {
+	var identifierType ServiceIdentifierType
+
+	if err := identifierType.UnmarshalGQL("SNOMED_CT"); err != nil || identifierType != ServiceIdentifierTypeSNOMEDCT {
+		t.Errorf("ServiceIdentifierType.UnmarshalGQL() = %v, %v, want SNOMED_CT", identifierType, err)
+	}
+
+	if err := identifierType.UnmarshalGQL("ICD"); err == nil {
+		t.Errorf("ServiceIdentifierType.UnmarshalGQL() expected an error for an invalid type")
+	}
+
+	if err := identifierType.UnmarshalGQL(1); err == nil {
+		t.Errorf("ServiceIdentifierType.UnmarshalGQL() expected an error for a non string value")
+	}
+}
+
+func TestServiceIdentifierType_MarshalGQL(t *testing.T) {
+	w := &bytes.Buffer{}
+
+	ServiceIdentifierTypeLOINC.MarshalGQL(w)
+
+	if got := w.String(); got != strconv.Quote("LOINC") {
+		t.Errorf("ServiceIdentifierType.MarshalGQL() = %v, want %v", got, strconv.Quote("LOINC"))
+	}
+}
@@ -0,0 +1,293 @@
+package healthcrm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrValidation matches API errors for requests that health CRM rejected as invalid (400 Bad Request)
+	ErrValidation = errors.New("health CRM rejected the request as invalid")
+
+	// ErrUnauthorized matches API errors for requests that were not authenticated (401 Unauthorized)
+	ErrUnauthorized = errors.New("health CRM request is unauthorized")
+
+	// ErrForbidden matches API errors for requests that are not permitted (403 Forbidden)
+	ErrForbidden = errors.New("health CRM request is forbidden")
+
+	// ErrNotFound matches API errors for resources that do not exist (404 Not Found)
+	ErrNotFound = errors.New("health CRM resource not found")
+
+	// ErrConflict matches API errors for requests that conflict with existing data (409 Conflict)
+	ErrConflict = errors.New("health CRM request conflicts with existing data")
+
+	// ErrRateLimited matches API errors for requests that were throttled (429 Too Many Requests)
+	ErrRateLimited = errors.New("health CRM request was rate limited")
+
+	// ErrServerError matches API errors for requests that failed on health CRM's side (5xx)
+	ErrServerError = errors.New("health CRM server error")
+)
+
+// requestIDHeader is the header health CRM uses to identify a request in its logs
+const requestIDHeader = "X-Request-ID"
+
+// APIError is returned when health CRM responds with an unexpected status code.
+//
+// It can be matched against the status sentinels e.g errors.Is(err, ErrNotFound),
+// or inspected with errors.As to get the status code and response body.
+type APIError struct {
+	StatusCode int
+	Method     string
+	Path       string
+	RequestID  string
+	// Body is the raw response body
+	Body []byte
+	// Detail is the error message health CRM returned in the "detail" field, if any
+	Detail string
+	// FieldErrors maps the path of each rejected input field e.g contacts[0].contact_value
+	// to health CRM's messages about it
+	FieldErrors map[string][]string
+	// NonFieldErrors holds messages about the input as a whole rather than a particular field
+	NonFieldErrors []string
+}
+
+// newAPIError builds an APIError from a response and its already read body
+func newAPIError(response *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: response.StatusCode,
+		RequestID:  response.Header.Get(requestIDHeader),
+		Body:       body,
+	}
+
+	if request := response.Request; request != nil {
+		apiErr.Method = request.Method
+		apiErr.Path = request.URL.Path
+
+		if apiErr.RequestID == "" {
+			apiErr.RequestID = request.Header.Get(requestIDHeader)
+		}
+	}
+
+	apiErr.Detail, apiErr.FieldErrors, apiErr.NonFieldErrors = parseErrorBody(body)
+
+	return apiErr
+}
+
+// newResponseError returns a ValidationError for a 400 response that names the rejected input,
+// and an APIError for any other unexpected response
+func newResponseError(response *http.Response, body []byte) error {
+	apiErr := newAPIError(response, body)
+
+	if apiErr.StatusCode == http.StatusBadRequest && (len(apiErr.FieldErrors) > 0 || len(apiErr.NonFieldErrors) > 0) {
+		return newValidationError(apiErr)
+	}
+
+	return apiErr
+}
+
+// nonFieldErrorsKey is the key Django REST framework uses for errors that are not about a particular field
+const nonFieldErrorsKey = "non_field_errors"
+
+// parseErrorBody reads a Django REST framework style error body e.g
+// {"detail": "Not found."} or {"name": ["This field is required."]}.
+// Errors about nested fields are flattened into paths such as contacts[0].contact_value.
+func parseErrorBody(body []byte) (string, map[string][]string, []string) {
+	var payload any
+
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", nil, nil
+	}
+
+	var detail string
+
+	if object, ok := payload.(map[string]any); ok {
+		if value, ok := object["detail"].(string); ok {
+			detail = value
+			delete(object, "detail")
+		}
+	}
+
+	fieldErrors := map[string][]string{}
+	flattenFieldErrors("", payload, fieldErrors)
+
+	nonFieldErrors := fieldErrors[""]
+	delete(fieldErrors, "")
+
+	if len(fieldErrors) == 0 {
+		fieldErrors = nil
+	}
+
+	return detail, fieldErrors, nonFieldErrors
+}
+
+// flattenFieldErrors adds the messages in value to fieldErrors under the path of the field they are about
+func flattenFieldErrors(path string, value any, fieldErrors map[string][]string) {
+	switch value := value.(type) {
+	case string:
+		fieldErrors[path] = append(fieldErrors[path], value)
+
+	case []any:
+		for i, item := range value {
+			if message, ok := item.(string); ok {
+				fieldErrors[path] = append(fieldErrors[path], message)
+				continue
+			}
+
+			flattenFieldErrors(fmt.Sprintf("%s[%d]", path, i), item, fieldErrors)
+		}
+
+	case map[string]any:
+		for key, item := range value {
+			flattenFieldErrors(fieldPath(path, key), item, fieldErrors)
+		}
+	}
+}
+
+// fieldPath returns the path of key within the field at path.
+// List fields report their errors in an object keyed by item index, which is written as path[index].
+func fieldPath(path, key string) string {
+	switch {
+	case key == nonFieldErrorsKey:
+		return path
+	case isIndex(key):
+		return fmt.Sprintf("%s[%s]", path, key)
+	case path == "":
+		return key
+	default:
+		return path + "." + key
+	}
+}
+
+// isIndex reports whether key is a list index
+func isIndex(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	for _, r := range key {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Error returns the request, the status code and what health CRM said about it
+func (e *APIError) Error() string {
+	message := e.Detail
+
+	if message == "" {
+		message = formatInputErrors(e.NonFieldErrors, e.FieldErrors)
+	}
+
+	if message == "" {
+		message = string(e.Body)
+	}
+
+	return fmt.Sprintf("health CRM %s %s responded with %d: %s", e.Method, e.Path, e.StatusCode, message)
+}
+
+// formatInputErrors lists the non field errors followed by the field errors sorted by path
+func formatInputErrors(nonFieldErrors []string, fieldErrors map[string][]string) string {
+	messages := slices.Clone(nonFieldErrors)
+
+	fields := make([]string, 0, len(fieldErrors))
+	for field, fieldMessages := range fieldErrors {
+		fields = append(fields, fmt.Sprintf("%s: %s", field, strings.Join(fieldMessages, " ")))
+	}
+
+	sort.Strings(fields)
+
+	return strings.Join(append(messages, fields...), "; ")
+}
+
+// Is matches the error against the sentinel for its status code
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServerError:
+		return e.StatusCode >= http.StatusInternalServerError
+	default:
+		return false
+	}
+}
+
+// FieldError holds health CRM's messages about one rejected input field
+type FieldError struct {
+	// Field is the path of the field in the request body e.g name or contacts[0].contact_value
+	Field    string
+	Messages []string
+}
+
+// ValidationError is returned when health CRM rejects a request's input with 400 Bad Request.
+//
+// It wraps the APIError for the response, so errors.Is(err, ErrValidation) and errors.As
+// to an *APIError keep working.
+type ValidationError struct {
+	// Fields lists the rejected input fields sorted by path
+	Fields []FieldError
+	// NonFieldErrors holds messages about the input as a whole rather than a particular field
+	NonFieldErrors []string
+	// Err is the API error for the response
+	Err *APIError
+}
+
+// newValidationError builds a ValidationError from a 400 response's API error
+func newValidationError(apiErr *APIError) *ValidationError {
+	validationErr := &ValidationError{
+		Fields:         make([]FieldError, 0, len(apiErr.FieldErrors)),
+		NonFieldErrors: apiErr.NonFieldErrors,
+		Err:            apiErr,
+	}
+
+	for field, messages := range apiErr.FieldErrors {
+		validationErr.Fields = append(validationErr.Fields, FieldError{Field: field, Messages: messages})
+	}
+
+	sort.Slice(validationErr.Fields, func(i, j int) bool {
+		return validationErr.Fields[i].Field < validationErr.Fields[j].Field
+	})
+
+	return validationErr
+}
+
+// Error lists what health CRM said about the rejected input
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf(
+		"health CRM %s %s rejected the input: %s",
+		e.Err.Method, e.Err.Path, formatInputErrors(e.NonFieldErrors, e.Err.FieldErrors),
+	)
+}
+
+// Unwrap returns the API error for the response
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Messages returns health CRM's messages about the field at path e.g contacts[0].contact_value
+func (e *ValidationError) Messages(path string) []string {
+	for _, field := range e.Fields {
+		if field.Field == path {
+			return field.Messages
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,292 @@
+package healthcrm
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestNewAPIError(t *testing.T) {
+	request := &http.Request{
+		Method: http.MethodPost,
+		URL:    &url.URL{Path: "/v1/facilities/facilities/"},
+		Header: http.Header{},
+	}
+
+	tests := []struct {
+		name            string
+		statusCode      int
+		body            string
+		wantDetail      string
+		wantFieldErrors map[string][]string
+		wantSentinel    error
+		wantMessage     string
+	}{
+		{
+			name:            "field errors",
+			statusCode:      http.StatusBadRequest,
+			body:            `{"name": ["This field is required."], "county": "Unknown county."}`,
+			wantFieldErrors: map[string][]string{"name": {"This field is required."}, "county": {"Unknown county."}},
+			wantSentinel:    ErrValidation,
+			wantMessage:     "health CRM POST /v1/facilities/facilities/ responded with 400: county: Unknown county.; name: This field is required.",
+		},
+		{
+			name:         "detail",
+			statusCode:   http.StatusNotFound,
+			body:         `{"detail": "Not found."}`,
+			wantDetail:   "Not found.",
+			wantSentinel: ErrNotFound,
+			wantMessage:  "health CRM POST /v1/facilities/facilities/ responded with 404: Not found.",
+		},
+		{
+			name:         "non JSON body",
+			statusCode:   http.StatusBadGateway,
+			body:         `<html>Bad Gateway</html>`,
+			wantSentinel: ErrServerError,
+			wantMessage:  "health CRM POST /v1/facilities/facilities/ responded with 502: <html>Bad Gateway</html>",
+		},
+		{
+			name:         "unauthorized",
+			statusCode:   http.StatusUnauthorized,
+			body:         `{"detail": "Invalid token."}`,
+			wantDetail:   "Invalid token.",
+			wantSentinel: ErrUnauthorized,
+			wantMessage:  "health CRM POST /v1/facilities/facilities/ responded with 401: Invalid token.",
+		},
+		{
+			name:         "forbidden",
+			statusCode:   http.StatusForbidden,
+			body:         `{}`,
+			wantSentinel: ErrForbidden,
+			wantMessage:  "health CRM POST /v1/facilities/facilities/ responded with 403: {}",
+		},
+		{
+			name:         "conflict",
+			statusCode:   http.StatusConflict,
+			body:         `{"detail": "Facility already exists."}`,
+			wantDetail:   "Facility already exists.",
+			wantSentinel: ErrConflict,
+			wantMessage:  "health CRM POST /v1/facilities/facilities/ responded with 409: Facility already exists.",
+		},
+		{
+			name:         "rate limited",
+			statusCode:   http.StatusTooManyRequests,
+			body:         `{"detail": "Request was throttled."}`,
+			wantDetail:   "Request was throttled.",
+			wantSentinel: ErrRateLimited,
+			wantMessage:  "health CRM POST /v1/facilities/facilities/ responded with 429: Request was throttled.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &http.Response{
+				StatusCode: tt.statusCode,
+				Header:     http.Header{},
+				Request:    request,
+			}
+			response.Header.Set(requestIDHeader, "req-123")
+
+			err := newAPIError(response, []byte(tt.body))
+
+			if err.Method != http.MethodPost || err.Path != "/v1/facilities/facilities/" || err.RequestID != "req-123" {
+				t.Errorf("newAPIError() = %+v, want the request's method, path and request ID", err)
+			}
+
+			if err.Detail != tt.wantDetail {
+				t.Errorf("newAPIError().Detail = %q, want %q", err.Detail, tt.wantDetail)
+			}
+
+			if len(err.FieldErrors) != len(tt.wantFieldErrors) {
+				t.Errorf("newAPIError().FieldErrors = %v, want %v", err.FieldErrors, tt.wantFieldErrors)
+			}
+
+			for field, messages := range tt.wantFieldErrors {
+				if len(err.FieldErrors[field]) != len(messages) || err.FieldErrors[field][0] != messages[0] {
+					t.Errorf("newAPIError().FieldErrors[%s] = %v, want %v", field, err.FieldErrors[field], messages)
+				}
+			}
+
+			if !errors.Is(err, tt.wantSentinel) {
+				t.Errorf("errors.Is(%v, %v) = false, want true", err, tt.wantSentinel)
+			}
+
+			for _, sentinel := range []error{ErrValidation, ErrUnauthorized, ErrForbidden, ErrNotFound, ErrConflict, ErrRateLimited, ErrServerError} {
+				if sentinel != tt.wantSentinel && errors.Is(err, sentinel) {
+					t.Errorf("errors.Is(%v, %v) = true, want false", err, sentinel)
+				}
+			}
+
+			if err.Error() != tt.wantMessage {
+				t.Errorf("APIError.Error() = %q, want %q", err.Error(), tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestParseErrorBody(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		wantDetail         string
+		wantFieldErrors    map[string][]string
+		wantNonFieldErrors []string
+	}{
+		{
+			name:            "top level fields",
+			body:            `{"name": ["This field is required.", "Ensure this field has no more than 100 characters."]}`,
+			wantFieldErrors: map[string][]string{"name": {"This field is required.", "Ensure this field has no more than 100 characters."}},
+		},
+		{
+			name: "nested list of objects",
+			body: `{"contacts": [{}, {"contact_value": ["Enter a valid phone number."], "contact_type": ["Invalid choice."]}]}`,
+			wantFieldErrors: map[string][]string{
+				"contacts[1].contact_value": {"Enter a valid phone number."},
+				"contacts[1].contact_type":  {"Invalid choice."},
+			},
+		},
+		{
+			name: "nested object",
+			body: `{"identifiers": {"identifier_value": ["This field may not be blank."]}}`,
+			wantFieldErrors: map[string][]string{
+				"identifiers.identifier_value": {"This field may not be blank."},
+			},
+		},
+		{
+			name: "list field keyed by index",
+			body: `{"service_ids": {"2": ["Must be a valid UUID."]}}`,
+			wantFieldErrors: map[string][]string{
+				"service_ids[2]": {"Must be a valid UUID."},
+			},
+		},
+		{
+			name:               "non field errors",
+			body:               `{"non_field_errors": ["The fields name, county must make a unique set."], "contacts": [{"non_field_errors": ["Duplicate contact."]}]}`,
+			wantFieldErrors:    map[string][]string{"contacts[0]": {"Duplicate contact."}},
+			wantNonFieldErrors: []string{"The fields name, county must make a unique set."},
+		},
+		{
+			name:               "list body",
+			body:               `["Facility is already active."]`,
+			wantNonFieldErrors: []string{"Facility is already active."},
+		},
+		{
+			name:       "detail",
+			body:       `{"detail": "Not found."}`,
+			wantDetail: "Not found.",
+		},
+		{
+			name: "not JSON",
+			body: `Bad Gateway`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detail, fieldErrors, nonFieldErrors := parseErrorBody([]byte(tt.body))
+
+			if detail != tt.wantDetail {
+				t.Errorf("parseErrorBody() detail = %q, want %q", detail, tt.wantDetail)
+			}
+
+			if !reflect.DeepEqual(fieldErrors, tt.wantFieldErrors) {
+				t.Errorf("parseErrorBody() field errors = %v, want %v", fieldErrors, tt.wantFieldErrors)
+			}
+
+			if !reflect.DeepEqual(nonFieldErrors, tt.wantNonFieldErrors) {
+				t.Errorf("parseErrorBody() non field errors = %v, want %v", nonFieldErrors, tt.wantNonFieldErrors)
+			}
+		})
+	}
+}
+
+func TestNewResponseError(t *testing.T) {
+	request := &http.Request{
+		Method: http.MethodPost,
+		URL:    &url.URL{Path: "/v1/facilities/facilities/"},
+		Header: http.Header{},
+	}
+
+	tests := []struct {
+		name           string
+		statusCode     int
+		body           string
+		wantValidation bool
+	}{
+		{
+			name:           "field errors",
+			statusCode:     http.StatusBadRequest,
+			body:           `{"contacts": [{"contact_value": ["Enter a valid phone number."]}]}`,
+			wantValidation: true,
+		},
+		{
+			name:           "bad request without field errors",
+			statusCode:     http.StatusBadRequest,
+			body:           `{"detail": "JSON parse error."}`,
+			wantValidation: false,
+		},
+		{
+			name:           "not a bad request",
+			statusCode:     http.StatusConflict,
+			body:           `{"name": ["Facility with this name already exists."]}`,
+			wantValidation: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &http.Response{StatusCode: tt.statusCode, Header: http.Header{}, Request: request}
+
+			err := newResponseError(response, []byte(tt.body))
+
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) != tt.wantValidation {
+				t.Fatalf("newResponseError() = %T, want a validation error: %v", err, tt.wantValidation)
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.statusCode {
+				t.Errorf("newResponseError() = %v, want an *APIError with status %d", err, tt.statusCode)
+			}
+		})
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	response := &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Header:     http.Header{},
+		Request: &http.Request{
+			Method: http.MethodPost,
+			URL:    &url.URL{Path: "/v1/facilities/facilities/"},
+			Header: http.Header{},
+		},
+	}
+	body := `{"name": ["This field is required."], "contacts": [{}, {"contact_value": ["Enter a valid phone number."]}], "non_field_errors": ["Invalid facility."]}`
+
+	err := newValidationError(newAPIError(response, []byte(body)))
+
+	wantFields := []FieldError{
+		{Field: "contacts[1].contact_value", Messages: []string{"Enter a valid phone number."}},
+		{Field: "name", Messages: []string{"This field is required."}},
+	}
+	if !reflect.DeepEqual(err.Fields, wantFields) {
+		t.Errorf("ValidationError.Fields = %v, want %v", err.Fields, wantFields)
+	}
+
+	if got := err.Messages("contacts[1].contact_value"); !reflect.DeepEqual(got, []string{"Enter a valid phone number."}) {
+		t.Errorf("ValidationError.Messages() = %v, want the contact value's messages", got)
+	}
+
+	if got := err.Messages("county"); got != nil {
+		t.Errorf("ValidationError.Messages() = %v, want nil for a field without errors", got)
+	}
+
+	wantMessage := "health CRM POST /v1/facilities/facilities/ rejected the input: Invalid facility.; contacts[1].contact_value: Enter a valid phone number.; name: This field is required."
+	if err.Error() != wantMessage {
+		t.Errorf("ValidationError.Error() = %q, want %q", err.Error(), wantMessage)
+	}
+
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("errors.Is(%v, ErrValidation) = false, want true", err)
+	}
+}
@@ -0,0 +1,531 @@
+package healthcrm
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+)
+
+const (
+	// FHIRFacilityTypeSystem is the coding system of the facility type in FHIR resources
+	FHIRFacilityTypeSystem = "urn:healthcrm:facility-type"
+
+	// FHIRContactRoleExtension is the extension on a FHIR contact point that holds the contact's role e.g HOTLINE
+	FHIRContactRoleExtension = "urn:healthcrm:fhir:contact-role"
+
+	// FHIRContactTypeExtension is the extension on a FHIR contact point that holds a contact type FHIR has no system for
+	FHIRContactTypeExtension = "urn:healthcrm:fhir:contact-type"
+
+	// fhirIdentifierSystemPrefix is followed by the identifier type in the system of identifiers without one e.g a new type
+	fhirIdentifierSystemPrefix = "urn:healthcrm:facility-identifier:"
+)
+
+// defaultFHIRIdentifierSystems maps each facility identifier type to the system of its FHIR identifiers
+var defaultFHIRIdentifierSystems = map[FacilityIdentifierType]string{
+	FacilityIdentifierTypeMFLCode:                "urn:healthcrm:facility-identifier:mfl-code",
+	FacilityIdentifierTypeHealthCRM:              "urn:healthcrm:facility-identifier:health-crm",
+	FacilityIdentifierTypeSladeCode:              "urn:healthcrm:facility-identifier:slade-code",
+	FacilityIdentifierTypeSHASladeCode:           "urn:healthcrm:facility-identifier:sha-slade-code",
+	FacilityIdentifierTypeFIDCode:                "urn:healthcrm:facility-identifier:fid-code",
+	FacilityIdentifierTypeFRCode:                 "urn:healthcrm:facility-identifier:fr-code",
+	FacilityIdentifierTypeKMPDCRegNumber:         "urn:healthcrm:facility-identifier:kmpdc-reg-number",
+	FacilityIdentifierTypeSladeAdvantageBranchID: "urn:healthcrm:facility-identifier:slade-advantage-branch-id",
+}
+
+// fhirOptions controls how facilities are converted to and from FHIR
+type fhirOptions struct {
+	identifierSystems map[FacilityIdentifierType]string
+}
+
+// FHIROption configures a FHIR conversion
+type FHIROption func(*fhirOptions)
+
+// WithFHIRIdentifierSystems sets the FHIR identifier systems of the given identifier types e.g a national registry's URL
+// for MFL codes. Types that are left out keep their default urn:healthcrm system.
+func WithFHIRIdentifierSystems(systems map[FacilityIdentifierType]string) FHIROption {
+	systems = maps.Clone(systems)
+
+	return func(o *fhirOptions) {
+		maps.Copy(o.identifierSystems, systems)
+	}
+}
+
+// newFHIROptions applies the options to the defaults
+func newFHIROptions(opts []FHIROption) fhirOptions {
+	o := fhirOptions{identifierSystems: maps.Clone(defaultFHIRIdentifierSystems)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// FHIRLocation is a FHIR R4 Location resource
+type FHIRLocation struct {
+	ResourceType         string                 `json:"resourceType"`
+	ID                   string                 `json:"id,omitempty"`
+	Identifier           []FHIRIdentifier       `json:"identifier,omitempty"`
+	Status               string                 `json:"status,omitempty"`
+	Name                 string                 `json:"name,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 []FHIRCodeableConcept  `json:"type,omitempty"`
+	Telecom              []FHIRContactPoint     `json:"telecom,omitempty"`
+	Address              *FHIRAddress           `json:"address,omitempty"`
+	Position             *FHIRPosition          `json:"position,omitempty"`
+	ManagingOrganization *FHIRReference         `json:"managingOrganization,omitempty"`
+	HoursOfOperation     []FHIRHoursOfOperation `json:"hoursOfOperation,omitempty"`
+}
+
+// FHIROrganization is a FHIR R4 Organization resource
+type FHIROrganization struct {
+	ResourceType string                `json:"resourceType"`
+	ID           string                `json:"id,omitempty"`
+	Identifier   []FHIRIdentifier      `json:"identifier,omitempty"`
+	Active       *bool                 `json:"active,omitempty"`
+	Type         []FHIRCodeableConcept `json:"type,omitempty"`
+	Name         string                `json:"name,omitempty"`
+	Telecom      []FHIRContactPoint    `json:"telecom,omitempty"`
+	Address      []FHIRAddress         `json:"address,omitempty"`
+}
+
+// FHIRIdentifier is a FHIR identifier
+type FHIRIdentifier struct {
+	System string      `json:"system,omitempty"`
+	Value  string      `json:"value,omitempty"`
+	Period *FHIRPeriod `json:"period,omitempty"`
+}
+
+// FHIRPeriod is a FHIR period of time
+type FHIRPeriod struct {
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+// FHIRCodeableConcept is a FHIR codeable concept
+type FHIRCodeableConcept struct {
+	Coding []FHIRCoding `json:"coding,omitempty"`
+	Text   string       `json:"text,omitempty"`
+}
+
+// FHIRCoding is a FHIR code from a coding system
+type FHIRCoding struct {
+	System string `json:"system,omitempty"`
+	Code   string `json:"code,omitempty"`
+}
+
+// FHIRContactPoint is a FHIR contact point e.g a phone number
+type FHIRContactPoint struct {
+	Extension []FHIRExtension `json:"extension,omitempty"`
+	System    string          `json:"system,omitempty"`
+	Value     string          `json:"value,omitempty"`
+	Use       string          `json:"use,omitempty"`
+}
+
+// FHIRExtension is a FHIR extension with a string value
+type FHIRExtension struct {
+	URL         string `json:"url"`
+	ValueString string `json:"valueString,omitempty"`
+}
+
+// FHIRAddress is a FHIR address
+type FHIRAddress struct {
+	Text     string `json:"text,omitempty"`
+	District string `json:"district,omitempty"`
+	Country  string `json:"country,omitempty"`
+}
+
+// FHIRPosition is the position of a FHIR location in decimal degrees
+type FHIRPosition struct {
+	Longitude float64 `json:"longitude"`
+	Latitude  float64 `json:"latitude"`
+}
+
+// FHIRReference is a reference to another FHIR resource
+type FHIRReference struct {
+	Reference string `json:"reference,omitempty"`
+}
+
+// FHIRHoursOfOperation is a period a FHIR location is open on the given days
+type FHIRHoursOfOperation struct {
+	DaysOfWeek  []string `json:"daysOfWeek,omitempty"`
+	AllDay      bool     `json:"allDay,omitempty"`
+	OpeningTime string   `json:"openingTime,omitempty"`
+	ClosingTime string   `json:"closingTime,omitempty"`
+}
+
+// fhirLocationStatuses maps facility statuses to FHIR location statuses
+var fhirLocationStatuses = map[FacilityStatus]string{
+	FacilityStatusPublished: "active",
+	FacilityStatusDraft:     "suspended",
+	FacilityStatusInactive:  "inactive",
+}
+
+// fhirContactSystems maps contact types to FHIR contact point systems
+var fhirContactSystems = map[ContactType]string{
+	ContactTypePhoneNumber: "phone",
+	ContactTypeEmail:       "email",
+}
+
+// ToFHIR converts the facility into a FHIR R4 Location and the Organization that manages it.
+// Both resources share the facility's ID, identifiers, type and contacts. Services and photos are not converted.
+func (f FacilityOutput) ToFHIR(opts ...FHIROption) (*FHIRLocation, *FHIROrganization) {
+	o := newFHIROptions(opts)
+
+	identifiers := fhirIdentifiers(f.Identifiers, o.identifierSystems)
+	telecom := fhirTelecom(f.Contacts)
+	facilityType := fhirFacilityType(f.FacilityType)
+
+	location := &FHIRLocation{
+		ResourceType:     "Location",
+		ID:               f.ID,
+		Identifier:       identifiers,
+		Status:           fhirLocationStatuses[f.Status],
+		Name:             f.Name,
+		Description:      f.Description,
+		Type:             facilityType,
+		Telecom:          telecom,
+		Address:          fhirAddress(f.Address, f.County, f.Country),
+		HoursOfOperation: fhirHoursOfOperation(f.BusinessHours),
+	}
+
+	if f.Coordinates != (CoordinatesOutput{}) {
+		location.Position = &FHIRPosition{Longitude: f.Coordinates.Longitude, Latitude: f.Coordinates.Latitude}
+	}
+
+	organization := &FHIROrganization{
+		ResourceType: "Organization",
+		ID:           f.ID,
+		Identifier:   identifiers,
+		Type:         facilityType,
+		Name:         f.Name,
+		Telecom:      telecom,
+	}
+
+	if f.Status != "" {
+		active := f.Status != FacilityStatusInactive
+		organization.Active = &active
+	}
+
+	if location.Address != nil {
+		organization.Address = []FHIRAddress{*location.Address}
+	}
+
+	if f.ID != "" {
+		location.ManagingOrganization = &FHIRReference{Reference: "Organization/" + f.ID}
+	}
+
+	return location, organization
+}
+
+// fhirIdentifiers converts facility identifiers. Types without a system are kept under urn:healthcrm:facility-identifier:<type>.
+func fhirIdentifiers(identifiers []IdentifiersOutput, systems map[FacilityIdentifierType]string) []FHIRIdentifier {
+	var converted []FHIRIdentifier
+
+	for _, identifier := range identifiers {
+		system, ok := systems[FacilityIdentifierType(identifier.IdentifierType)]
+		if !ok {
+			system = fhirIdentifierSystemPrefix + identifier.IdentifierType
+		}
+
+		fhirIdentifier := FHIRIdentifier{System: system, Value: identifier.IdentifierValue}
+
+		if identifier.ValidFrom != "" || identifier.ValidTo != "" {
+			fhirIdentifier.Period = &FHIRPeriod{Start: fhirDate(identifier.ValidFrom), End: fhirDate(identifier.ValidTo)}
+		}
+
+		converted = append(converted, fhirIdentifier)
+	}
+
+	return converted
+}
+
+// fhirDate returns the date part of a date or timestamp
+func fhirDate(value string) string {
+	if len(value) > len(identifierDateLayout) {
+		return value[:len(identifierDateLayout)]
+	}
+
+	return value
+}
+
+// fhirTelecom converts facility contacts. Inactive contacts are kept with the use "old", and contact types
+// FHIR has no system for are kept with the system "other" and their type in an extension.
+func fhirTelecom(contacts []ContactsOutput) []FHIRContactPoint {
+	var telecom []FHIRContactPoint
+
+	for _, contact := range contacts {
+		point := FHIRContactPoint{Value: contact.ContactValue, Use: "work"}
+
+		system, ok := fhirContactSystems[ContactType(contact.ContactType)]
+		if !ok {
+			system = "other"
+			point.Extension = append(point.Extension, FHIRExtension{URL: FHIRContactTypeExtension, ValueString: contact.ContactType})
+		}
+
+		point.System = system
+
+		if !contact.Active {
+			point.Use = "old"
+		}
+
+		if contact.Role != "" {
+			point.Extension = append(point.Extension, FHIRExtension{URL: FHIRContactRoleExtension, ValueString: contact.Role})
+		}
+
+		telecom = append(telecom, point)
+	}
+
+	return telecom
+}
+
+// fhirFacilityType converts the facility type into a FHIR type
+func fhirFacilityType(facilityType string) []FHIRCodeableConcept {
+	if facilityType == "" {
+		return nil
+	}
+
+	return []FHIRCodeableConcept{{Coding: []FHIRCoding{{System: FHIRFacilityTypeSystem, Code: facilityType}}}}
+}
+
+// fhirAddress converts the facility's address, or returns nil when it has none
+func fhirAddress(address, county, country string) *FHIRAddress {
+	if address == "" && county == "" && country == "" {
+		return nil
+	}
+
+	return &FHIRAddress{Text: address, District: county, Country: country}
+}
+
+// fhirHoursOfOperation converts business hours, one FHIR entry per day
+func fhirHoursOfOperation(hours []BusinessHoursOutput) []FHIRHoursOfOperation {
+	var converted []FHIRHoursOfOperation
+
+	for _, h := range hours {
+		period, err := h.Period()
+		if err != nil {
+			converted = append(converted, FHIRHoursOfOperation{
+				DaysOfWeek:  []string{fhirDay(h.Day)},
+				OpeningTime: h.OpeningTime,
+				ClosingTime: h.ClosingTime,
+			})
+
+			continue
+		}
+
+		if period.IsAllDay() {
+			converted = append(converted, FHIRHoursOfOperation{DaysOfWeek: []string{fhirDay(h.Day)}, AllDay: true})
+			continue
+		}
+
+		converted = append(converted, FHIRHoursOfOperation{
+			DaysOfWeek:  []string{fhirDay(h.Day)},
+			OpeningTime: fhirTime(period.Opens),
+			ClosingTime: fhirTime(period.Closes),
+		})
+	}
+
+	return converted
+}
+
+// fhirDay converts a day e.g MONDAY into a FHIR day of the week e.g mon
+func fhirDay(day string) string {
+	day = strings.ToLower(strings.TrimSpace(day))
+	if len(day) > 3 {
+		return day[:3]
+	}
+
+	return day
+}
+
+// fhirTime formats a time of day as a FHIR time. FHIR has no 24:00, so the end of the day is 23:59:59.
+func fhirTime(t TimeOfDay) string {
+	if t >= EndOfDay {
+		t = EndOfDay - 1
+	}
+
+	seconds := int(t)
+
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds%3600/60, seconds%60)
+}
+
+// FacilityFromFHIR converts a FHIR R4 Location into a facility that can be created in health CRM.
+// The location's logical id belongs to the system it came from, so the facility's ID is left empty.
+// Contact points with the use "old" are left out as a new facility's contacts are all in use. An identifier
+// system or contact point that cannot be converted is an error rather than being dropped.
+func FacilityFromFHIR(location FHIRLocation, opts ...FHIROption) (*Facility, error) {
+	o := newFHIROptions(opts)
+
+	if location.ResourceType != "Location" {
+		return nil, fmt.Errorf("expected a FHIR Location, got %q", location.ResourceType)
+	}
+
+	if location.Name == "" {
+		return nil, errors.New("FHIR Location must have a name")
+	}
+
+	facility := &Facility{
+		Name:        location.Name,
+		Description: location.Description,
+	}
+
+	for _, concept := range location.Type {
+		for _, coding := range concept.Coding {
+			if coding.System == FHIRFacilityTypeSystem {
+				facility.FacilityType = coding.Code
+			}
+		}
+	}
+
+	if location.Address != nil {
+		facility.Address = location.Address.Text
+		facility.County = location.Address.District
+		facility.Country = location.Address.Country
+	}
+
+	if location.Position != nil {
+		point, err := NewGeoPoint(location.Position.Latitude, location.Position.Longitude)
+		if err != nil {
+			return nil, err
+		}
+
+		facility.Coordinates = &Coordinates{
+			Latitude:  strconv.FormatFloat(point.Latitude, 'f', -1, 64),
+			Longitude: strconv.FormatFloat(point.Longitude, 'f', -1, 64),
+		}
+	}
+
+	identifiers, err := facilityIdentifiersFromFHIR(location.Identifier, o.identifierSystems)
+	if err != nil {
+		return nil, err
+	}
+
+	facility.Identifiers = identifiers
+
+	contacts, err := facilityContactsFromFHIR(location.Telecom)
+	if err != nil {
+		return nil, err
+	}
+
+	facility.Contacts = contacts
+
+	hours, err := businessHoursFromFHIR(location.HoursOfOperation)
+	if err != nil {
+		return nil, err
+	}
+
+	facility.BusinessHours = hours
+
+	return facility, nil
+}
+
+// facilityIdentifiersFromFHIR converts FHIR identifiers, reading the type from the system
+func facilityIdentifiersFromFHIR(identifiers []FHIRIdentifier, systems map[FacilityIdentifierType]string) ([]Identifiers, error) {
+	// identifiers converted without WithFHIRIdentifierSystems can still be read back
+	types := make(map[string]FacilityIdentifierType, len(defaultFHIRIdentifierSystems)+len(systems))
+	for identifierType, system := range defaultFHIRIdentifierSystems {
+		types[system] = identifierType
+	}
+
+	for identifierType, system := range systems {
+		types[system] = identifierType
+	}
+
+	var converted []Identifiers
+
+	for _, identifier := range identifiers {
+		identifierType, ok := types[identifier.System]
+		if !ok {
+			identifierType = FacilityIdentifierType(strings.TrimPrefix(identifier.System, fhirIdentifierSystemPrefix))
+		}
+
+		if identifierType == "" || identifierType.String() == identifier.System {
+			return nil, fmt.Errorf("unknown FHIR identifier system %q", identifier.System)
+		}
+
+		facilityIdentifier := Identifiers{IdentifierType: identifierType.String(), IdentifierValue: identifier.Value}
+
+		if identifier.Period != nil {
+			facilityIdentifier.ValidFrom = identifier.Period.Start
+			facilityIdentifier.ValidTo = identifier.Period.End
+		}
+
+		converted = append(converted, facilityIdentifier)
+	}
+
+	return converted, nil
+}
+
+// facilityContactsFromFHIR converts FHIR contact points that are in use
+func facilityContactsFromFHIR(telecom []FHIRContactPoint) ([]Contacts, error) {
+	var converted []Contacts
+
+	for _, point := range telecom {
+		if point.Use == "old" {
+			continue
+		}
+
+		var contactType ContactType
+
+		for t, system := range fhirContactSystems {
+			if system == point.System {
+				contactType = t
+			}
+		}
+
+		contact := Contacts{ContactType: contactType.String(), ContactValue: point.Value}
+
+		for _, extension := range point.Extension {
+			switch extension.URL {
+			case FHIRContactRoleExtension:
+				contact.Role = extension.ValueString
+			case FHIRContactTypeExtension:
+				contact.ContactType = extension.ValueString
+			}
+		}
+
+		if contact.ContactType == "" {
+			return nil, fmt.Errorf("unsupported FHIR contact point system %q", point.System)
+		}
+
+		converted = append(converted, contact)
+	}
+
+	return converted, nil
+}
+
+// fhirDays maps FHIR days of the week to health CRM days
+var fhirDays = map[string]DayOfWeek{
+	"mon": DayOfWeekMonday,
+	"tue": DayOfWeekTuesday,
+	"wed": DayOfWeekWednesday,
+	"thu": DayOfWeekThursday,
+	"fri": DayOfWeekFriday,
+	"sat": DayOfWeekSaturday,
+	"sun": DayOfWeekSunday,
+}
+
+// businessHoursFromFHIR converts FHIR hours of operation into business hours, one per day
+func businessHoursFromFHIR(hours []FHIRHoursOfOperation) ([]BusinessHours, error) {
+	var converted []BusinessHours
+
+	for _, h := range hours {
+		opening, closing := h.OpeningTime, h.ClosingTime
+		if h.AllDay {
+			// health CRM's all day hours are 00:00 to 23:59
+			opening, closing = "00:00:00", "23:59:00"
+		}
+
+		for _, day := range h.DaysOfWeek {
+			dayOfWeek, ok := fhirDays[day]
+			if !ok {
+				return nil, fmt.Errorf("invalid FHIR day of the week %q", day)
+			}
+
+			converted = append(converted, BusinessHours{Day: dayOfWeek.String(), OpeningTime: opening, ClosingTime: closing})
+		}
+	}
+
+	return converted, nil
+}
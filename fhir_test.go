@@ -0,0 +1,223 @@
+package healthcrm
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+// assertGolden compares value, as indented JSON, with a golden file in testdata/fhir
+func assertGolden(t *testing.T, name string, value any) {
+	t.Helper()
+
+	got, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		t.Fatalf("unable to marshal %s: %v", name, err)
+	}
+
+	got = append(got, '\n')
+	path := filepath.Join("testdata", "fhir", name)
+
+	if *updateGolden {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("unable to update %s: %v", path, err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unable to read %s: %v", path, err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s does not match the golden file:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+// readTestData decodes a JSON file in testdata/fhir
+func readTestData(t *testing.T, name string, value any) {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "fhir", name))
+	if err != nil {
+		t.Fatalf("unable to read %s: %v", name, err)
+	}
+
+	if err := json.Unmarshal(data, value); err != nil {
+		t.Fatalf("unable to decode %s: %v", name, err)
+	}
+}
+
+func TestFacilityOutput_ToFHIR(t *testing.T) {
+	var facility FacilityOutput
+	readTestData(t, "facility_output.json", &facility)
+
+	location, organization := facility.ToFHIR()
+
+	assertGolden(t, "location.golden.json", location)
+	assertGolden(t, "organization.golden.json", organization)
+}
+
+func TestFacilityOutput_ToFHIR_Minimal(t *testing.T) {
+	location, organization := FacilityOutput{Name: "Clinic"}.ToFHIR()
+
+	if location.Position != nil || location.Address != nil || location.ManagingOrganization != nil || location.Status != "" {
+		t.Errorf("FacilityOutput.ToFHIR() location = %+v, want only the name", location)
+	}
+
+	if organization.Active != nil || organization.Address != nil {
+		t.Errorf("FacilityOutput.ToFHIR() organization = %+v, want only the name", organization)
+	}
+}
+
+func TestFacilityFromFHIR(t *testing.T) {
+	var location FHIRLocation
+	readTestData(t, "location.json", &location)
+
+	facility, err := FacilityFromFHIR(location)
+	if err != nil {
+		t.Fatalf("FacilityFromFHIR() error = %v", err)
+	}
+
+	assertGolden(t, "facility.golden.json", facility)
+}
+
+func TestFacilityFromFHIR_RoundTrip(t *testing.T) {
+	var output FacilityOutput
+	readTestData(t, "facility_output.json", &output)
+
+	location, _ := output.ToFHIR()
+
+	facility, err := FacilityFromFHIR(*location)
+	if err != nil {
+		t.Fatalf("FacilityFromFHIR() error = %v", err)
+	}
+
+	// everything but the ID and the inactive contact comes back; timestamps are kept as dates
+	want := &Facility{
+		Name:         "Mbagathi County Hospital",
+		Description:  "County referral hospital",
+		FacilityType: "HOSPITAL",
+		County:       "Nairobi",
+		Country:      "KE",
+		Address:      "Mbagathi Way",
+		Coordinates:  &Coordinates{Latitude: "-1.30817", Longitude: "36.80373"},
+		Contacts: []Contacts{
+			{ContactType: "PHONE_NUMBER", ContactValue: "+254712345678", Role: "HOTLINE"},
+			{ContactType: "EMAIL", ContactValue: "info@mbagathi.example.com"},
+			{ContactType: "WHATSAPP", ContactValue: "+254711111111", Role: "BOOKINGS"},
+		},
+		Identifiers: []Identifiers{
+			{IdentifierType: "MFL_CODE", IdentifierValue: "13023", ValidFrom: "2020-01-01"},
+			{IdentifierType: "SHA_SLADE_CODE", IdentifierValue: "SHA-13023", ValidFrom: "2024-07-01", ValidTo: "2025-06-30"},
+			{IdentifierType: "NHIF_CODE", IdentifierValue: "NHIF-13023"},
+		},
+		BusinessHours: []BusinessHours{
+			{Day: "MONDAY", OpeningTime: "08:00:00", ClosingTime: "17:00:00"},
+			{Day: "SATURDAY", OpeningTime: "00:00:00", ClosingTime: "23:59:00"},
+			{Day: "FRIDAY", OpeningTime: "20:00:00", ClosingTime: "06:00:00"},
+		},
+	}
+
+	if !reflect.DeepEqual(facility, want) {
+		t.Errorf("FacilityFromFHIR() did not round trip:\n%+v\nwant:\n%+v", facility, want)
+	}
+}
+
+func TestWithFHIRIdentifierSystems(t *testing.T) {
+	const registry = "https://registry.example.com/mfl"
+
+	systems := map[FacilityIdentifierType]string{FacilityIdentifierTypeMFLCode: registry}
+	opt := WithFHIRIdentifierSystems(systems)
+
+	// changing the map after the option is created does not change the conversion
+	systems[FacilityIdentifierTypeMFLCode] = "https://other.example.com/mfl"
+
+	output := FacilityOutput{
+		Name: "Clinic",
+		Identifiers: []IdentifiersOutput{
+			{IdentifierType: "MFL_CODE", IdentifierValue: "12345"},
+			{IdentifierType: "SLADE_CODE", IdentifierValue: "678"},
+		},
+	}
+
+	location, _ := output.ToFHIR(opt)
+
+	want := []FHIRIdentifier{
+		{System: registry, Value: "12345"},
+		{System: defaultFHIRIdentifierSystems[FacilityIdentifierTypeSladeCode], Value: "678"},
+	}
+	if !reflect.DeepEqual(location.Identifier, want) {
+		t.Errorf("FacilityOutput.ToFHIR() identifiers = %+v, want %+v", location.Identifier, want)
+	}
+
+	facility, err := FacilityFromFHIR(*location, opt)
+	if err != nil {
+		t.Fatalf("FacilityFromFHIR() error = %v", err)
+	}
+
+	if len(facility.Identifiers) != 2 || facility.Identifiers[0].IdentifierType != "MFL_CODE" {
+		t.Errorf("FacilityFromFHIR() identifiers = %+v, want the MFL and Slade codes", facility.Identifiers)
+	}
+
+	if defaultFHIRIdentifierSystems[FacilityIdentifierTypeMFLCode] == registry {
+		t.Errorf("WithFHIRIdentifierSystems() changed the default systems")
+	}
+}
+
+func TestFacilityFromFHIR_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		location FHIRLocation
+	}{
+		{
+			name:     "Sad case: not a location",
+			location: FHIRLocation{ResourceType: "Organization", Name: "Clinic"},
+		},
+		{
+			name:     "Sad case: missing name",
+			location: FHIRLocation{ResourceType: "Location"},
+		},
+		{
+			name:     "Sad case: position out of range",
+			location: FHIRLocation{ResourceType: "Location", Name: "Clinic", Position: &FHIRPosition{Longitude: -1.3, Latitude: 136.8}},
+		},
+		{
+			name: "Sad case: unknown identifier system",
+			location: FHIRLocation{ResourceType: "Location", Name: "Clinic", Identifier: []FHIRIdentifier{
+				{System: "https://emr.example.com/location-id", Value: "LOC-9"},
+			}},
+		},
+		{
+			name: "Sad case: identifier system without a type",
+			location: FHIRLocation{ResourceType: "Location", Name: "Clinic", Identifier: []FHIRIdentifier{
+				{System: "urn:healthcrm:facility-identifier:", Value: "LOC-9"},
+			}},
+		},
+		{
+			name: "Sad case: unsupported contact point",
+			location: FHIRLocation{ResourceType: "Location", Name: "Clinic", Telecom: []FHIRContactPoint{
+				{System: "fax", Value: "+254200000000"},
+			}},
+		},
+		{
+			name: "Sad case: invalid day",
+			location: FHIRLocation{ResourceType: "Location", Name: "Clinic", HoursOfOperation: []FHIRHoursOfOperation{
+				{DaysOfWeek: []string{"monday"}, OpeningTime: "08:00:00", ClosingTime: "17:00:00"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FacilityFromFHIR(tt.location); err == nil {
+				t.Errorf("FacilityFromFHIR() expected an error")
+			}
+		})
+	}
+}
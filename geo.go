@@ -0,0 +1,209 @@
+package healthcrm
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// earthRadius is the mean radius of the earth used for haversine distances
+const earthRadius = 6371 * Kilometer
+
+// Distance is a distance in metres. Use the unit constants to give it explicit units e.g 5 * Kilometer.
+type Distance float64
+
+// Units of distance
+const (
+	Meter     Distance = 1
+	Kilometer Distance = 1000 * Meter
+	Mile      Distance = 1609.344 * Meter
+)
+
+// Meters returns the distance in metres
+func (d Distance) Meters() float64 {
+	return float64(d)
+}
+
+// Kilometers returns the distance in kilometres
+func (d Distance) Kilometers() float64 {
+	return float64(d / Kilometer)
+}
+
+// Miles returns the distance in miles
+func (d Distance) Miles() float64 {
+	return float64(d / Mile)
+}
+
+// String formats the distance in kilometres e.g 2.5km
+func (d Distance) String() string {
+	return strconv.FormatFloat(d.Kilometers(), 'f', -1, 64) + "km"
+}
+
+// GeoPoint is a location given by its latitude and longitude in decimal degrees
+type GeoPoint struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// NewGeoPoint returns the point at latitude and longitude, checking that both are in range
+func NewGeoPoint(latitude, longitude float64) (GeoPoint, error) {
+	point := GeoPoint{Latitude: latitude, Longitude: longitude}
+
+	if err := point.Validate(); err != nil {
+		return GeoPoint{}, err
+	}
+
+	return point, nil
+}
+
+// Validate checks that the latitude is between -90 and 90 and the longitude between -180 and 180
+func (p GeoPoint) Validate() error {
+	if math.IsNaN(p.Latitude) || p.Latitude < -90 || p.Latitude > 90 {
+		return fmt.Errorf("invalid latitude %v: must be between -90 and 90", p.Latitude)
+	}
+
+	if math.IsNaN(p.Longitude) || p.Longitude < -180 || p.Longitude > 180 {
+		return fmt.Errorf("invalid longitude %v: must be between -180 and 180", p.Longitude)
+	}
+
+	return nil
+}
+
+// String formats the point as "latitude, longitude"
+func (p GeoPoint) String() string {
+	return fmt.Sprintf("%s, %s", formatDegrees(p.Latitude), formatDegrees(p.Longitude))
+}
+
+// refLocation formats the point as "longitude, latitude", the order health CRM expects for ref_location
+func (p GeoPoint) refLocation() string {
+	return fmt.Sprintf("%s, %s", formatDegrees(p.Longitude), formatDegrees(p.Latitude))
+}
+
+// formatDegrees formats a latitude or longitude with up to 5 decimal places, about a metre
+func formatDegrees(degrees float64) string {
+	return strconv.FormatFloat(math.Round(degrees*1e5)/1e5, 'f', -1, 64)
+}
+
+// GeoPoint parses the coordinates' latitude and longitude into a range checked point
+func (c Coordinates) GeoPoint() (GeoPoint, error) {
+	if c.Latitude == "" || c.Longitude == "" {
+		return GeoPoint{}, fmt.Errorf("both Latitude and Longitude must be provided")
+	}
+
+	latitude, err := strconv.ParseFloat(c.Latitude, 64)
+	if err != nil {
+		return GeoPoint{}, fmt.Errorf("invalid latitude %q", c.Latitude)
+	}
+
+	longitude, err := strconv.ParseFloat(c.Longitude, 64)
+	if err != nil {
+		return GeoPoint{}, fmt.Errorf("invalid longitude %q", c.Longitude)
+	}
+
+	return NewGeoPoint(latitude, longitude)
+}
+
+// GeoPoint returns the coordinates as a point
+func (c CoordinatesOutput) GeoPoint() GeoPoint {
+	return GeoPoint{Latitude: c.Latitude, Longitude: c.Longitude}
+}
+
+// samePoint reports whether two points are the same to 5 decimal places, about a metre
+func samePoint(a, b GeoPoint) bool {
+	return math.Abs(a.Latitude-b.Latitude) < 5e-6 && math.Abs(a.Longitude-b.Longitude) < 5e-6
+}
+
+// HaversineDistance returns the great-circle distance between two points
+func HaversineDistance(from, to GeoPoint) Distance {
+	lat1, lat2 := from.Latitude*math.Pi/180, to.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (to.Longitude - from.Longitude) * math.Pi / 180
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return Distance(2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a)) * float64(earthRadius))
+}
+
+// DistanceFrom returns the distance between the facility and a point, computed from the facility's coordinates
+func (f FacilityOutput) DistanceFrom(point GeoPoint) Distance {
+	return HaversineDistance(point, f.Coordinates.GeoPoint())
+}
+
+// AnnotateDistances sets the Distance, in kilometres, of the facilities that health CRM returned without one.
+// Facilities without coordinates are left as they are.
+func AnnotateDistances(facilities []FacilityOutput, from GeoPoint) {
+	for i := range facilities {
+		if facilities[i].Distance != 0 || !hasCoordinates(facilities[i]) {
+			continue
+		}
+
+		facilities[i].Distance = facilities[i].DistanceFrom(from).Kilometers()
+	}
+}
+
+// SortByDistance sorts the facilities nearest first by their distance from a point.
+// Facilities without coordinates are moved to the end.
+func SortByDistance(facilities []FacilityOutput, from GeoPoint) {
+	sort.SliceStable(facilities, func(i, j int) bool {
+		iHas, jHas := hasCoordinates(facilities[i]), hasCoordinates(facilities[j])
+		if iHas != jHas {
+			return iHas
+		}
+
+		return facilities[i].DistanceFrom(from) < facilities[j].DistanceFrom(from)
+	})
+}
+
+// hasCoordinates reports whether health CRM returned coordinates for the facility
+func hasCoordinates(facility FacilityOutput) bool {
+	return facility.Coordinates != CoordinatesOutput{}
+}
+
+// addLocationFilter adds the point that facilities are ordered by proximity to, and the search radius, to a
+// request's query parameters. Health CRM reads the radius in kilometres.
+func addLocationFilter(queryParams url.Values, location *Coordinates, near *GeoPoint, radius Distance) error {
+	if location != nil && near != nil {
+		return errors.New("both location and near cannot be provided simultaneously")
+	}
+
+	if radius < 0 {
+		return errors.New("radius must not be negative")
+	}
+
+	switch {
+	case location != nil:
+		if radius != 0 {
+			return errors.New("radius must be provided in the location when location is used")
+		}
+
+		coordinateString, err := location.ToString()
+		if err != nil {
+			return err
+		}
+
+		queryParams.Add("ref_location", coordinateString)
+
+		if location.Radius != "" {
+			queryParams.Add("distance", location.Radius)
+		}
+
+	case near != nil:
+		if err := near.Validate(); err != nil {
+			return err
+		}
+
+		queryParams.Add("ref_location", near.refLocation())
+
+		if radius > 0 {
+			queryParams.Add("distance", strconv.FormatFloat(radius.Kilometers(), 'f', -1, 64))
+		}
+
+	case radius != 0:
+		return errors.New("a point to search near must be provided with the radius")
+	}
+
+	return nil
+}
@@ -0,0 +1,235 @@
+package healthcrm
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestNewGeoPoint(t *testing.T) {
+	tests := []struct {
+		name      string
+		latitude  float64
+		longitude float64
+		wantErr   bool
+	}{
+		{name: "Happy case: Nairobi", latitude: -1.29, longitude: 36.79},
+		{name: "Happy case: edge of range", latitude: 90, longitude: -180},
+		{name: "Sad case: latitude out of range", latitude: 91, longitude: 36.79, wantErr: true},
+		{name: "Sad case: swapped latitude and longitude", latitude: 136.79, longitude: -1.29, wantErr: true},
+		{name: "Sad case: longitude out of range", latitude: -1.29, longitude: 180.5, wantErr: true},
+		{name: "Sad case: not a number", latitude: math.NaN(), longitude: 36.79, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewGeoPoint(tt.latitude, tt.longitude)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewGeoPoint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCoordinates_ToString(t *testing.T) {
+	tests := []struct {
+		name        string
+		coordinates Coordinates
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:        "Happy case: longitude first",
+			coordinates: Coordinates{Latitude: "-1.29", Longitude: "36.79"},
+			want:        "36.79, -1.29",
+		},
+		{
+			name:        "Happy case: rounded to 5 decimal places",
+			coordinates: Coordinates{Latitude: "-1.2921234", Longitude: "36.8219462"},
+			want:        "36.82195, -1.29212",
+		},
+		{
+			name:        "Sad case: missing longitude",
+			coordinates: Coordinates{Latitude: "-1.29"},
+			wantErr:     true,
+		},
+		{
+			name:        "Sad case: latitude out of range",
+			coordinates: Coordinates{Latitude: "136.79", Longitude: "-1.29"},
+			wantErr:     true,
+		},
+		{
+			name:        "Sad case: not a number",
+			coordinates: Coordinates{Latitude: "south", Longitude: "36.79"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.coordinates.ToString()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Coordinates.ToString() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Coordinates.ToString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistance_Units(t *testing.T) {
+	d := 2500 * Meter
+
+	if d.Kilometers() != 2.5 || d.Meters() != 2500 {
+		t.Errorf("Distance = %v km, %v m, want 2.5 km, 2500 m", d.Kilometers(), d.Meters())
+	}
+
+	if miles := (10 * Mile).Kilometers(); math.Abs(miles-16.09344) > 1e-9 {
+		t.Errorf("10 miles = %v km, want 16.09344", miles)
+	}
+
+	if got := d.String(); got != "2.5km" {
+		t.Errorf("Distance.String() = %v, want 2.5km", got)
+	}
+}
+
+func TestHaversineDistance(t *testing.T) {
+	nairobi := GeoPoint{Latitude: -1.2921, Longitude: 36.8219}
+	mombasa := GeoPoint{Latitude: -4.0435, Longitude: 39.6682}
+
+	// Nairobi to Mombasa is about 440km as the crow flies
+	if got := HaversineDistance(nairobi, mombasa).Kilometers(); math.Abs(got-440) > 5 {
+		t.Errorf("HaversineDistance() = %vkm, want about 440km", got)
+	}
+
+	if got := HaversineDistance(nairobi, nairobi); got != 0 {
+		t.Errorf("HaversineDistance() = %v, want 0 for the same point", got)
+	}
+
+	if HaversineDistance(nairobi, mombasa) != HaversineDistance(mombasa, nairobi) {
+		t.Errorf("HaversineDistance() expected the same distance in both directions")
+	}
+}
+
+func TestSortByDistance(t *testing.T) {
+	nairobi := GeoPoint{Latitude: -1.2921, Longitude: 36.8219}
+
+	facilities := []FacilityOutput{
+		{ID: "mombasa", Coordinates: CoordinatesOutput{Latitude: -4.0435, Longitude: 39.6682}},
+		{ID: "unknown"},
+		{ID: "thika", Coordinates: CoordinatesOutput{Latitude: -1.0333, Longitude: 37.0693}},
+		{ID: "kisumu", Coordinates: CoordinatesOutput{Latitude: -0.0917, Longitude: 34.768}, Distance: 265},
+	}
+
+	AnnotateDistances(facilities, nairobi)
+
+	if facilities[1].Distance != 0 {
+		t.Errorf("AnnotateDistances() set a distance for a facility without coordinates")
+	}
+
+	if facilities[3].Distance != 265 {
+		t.Errorf("AnnotateDistances() replaced the distance health CRM returned")
+	}
+
+	if math.Abs(facilities[0].Distance-440) > 5 {
+		t.Errorf("AnnotateDistances() = %vkm, want about 440km", facilities[0].Distance)
+	}
+
+	SortByDistance(facilities, nairobi)
+
+	want := []string{"thika", "kisumu", "mombasa", "unknown"}
+	for i, id := range want {
+		if facilities[i].ID != id {
+			t.Errorf("SortByDistance()[%d] = %s, want %s", i, facilities[i].ID, id)
+		}
+	}
+}
+
+func TestHealthCRMLib_GetFacilities_Location(t *testing.T) {
+	ctx := context.Background()
+	path := fmt.Sprintf("%s/v1/facilities/facilities/", baseURL)
+	nairobi := &GeoPoint{Latitude: -1.29, Longitude: 36.79}
+
+	tests := []struct {
+		name         string
+		filters      FilterFacilitiesInput
+		wantLocation string
+		wantDistance string
+		wantErr      bool
+	}{
+		{
+			name:         "Happy case: near a point within a radius",
+			filters:      FilterFacilitiesInput{Near: nairobi, Radius: 2500 * Meter},
+			wantLocation: "36.79, -1.29",
+			wantDistance: "2.5",
+		},
+		{
+			name:         "Happy case: near a point",
+			filters:      FilterFacilitiesInput{Near: nairobi},
+			wantLocation: "36.79, -1.29",
+		},
+		{
+			name:         "Happy case: location as strings",
+			filters:      FilterFacilitiesInput{Location: &Coordinates{Latitude: "-1.29", Longitude: "36.79", Radius: "10"}},
+			wantLocation: "36.79, -1.29",
+			wantDistance: "10",
+		},
+		{
+			name:    "Sad case: invalid point",
+			filters: FilterFacilitiesInput{Near: &GeoPoint{Latitude: 136.79, Longitude: -1.29}},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: radius without a point",
+			filters: FilterFacilitiesInput{Radius: 10 * Kilometer},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: negative radius",
+			filters: FilterFacilitiesInput{Near: nairobi, Radius: -1 * Kilometer},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: both location and near",
+			filters: FilterFacilitiesInput{Near: nairobi, Location: &Coordinates{Latitude: "-1.29", Longitude: "36.79"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			MockAuthenticate()
+
+			var location, distance string
+
+			httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
+				location = r.URL.Query().Get("ref_location")
+				distance = r.URL.Query().Get("distance")
+
+				return httpmock.NewJsonResponse(http.StatusOK, &FacilityPage{})
+			})
+
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
+			if err != nil {
+				t.Fatalf("unable to initialize sdk: %v", err)
+			}
+			defer h.Close()
+
+			tt.filters.CrmServiceCode = "05"
+
+			_, err = h.GetFacilities(ctx, tt.filters)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HealthCRMLib.GetFacilities() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if location != tt.wantLocation || distance != tt.wantDistance {
+				t.Errorf("HealthCRMLib.GetFacilities() sent ref_location=%q distance=%q, want %q and %q", location, distance, tt.wantLocation, tt.wantDistance)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package healthcrm
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"time"
+)
+
+// geoJSONOptions controls which facility details are exported to GeoJSON
+type geoJSONOptions struct {
+	identifierTypes []FacilityIdentifierType
+}
+
+// GeoJSONOption configures a GeoJSON export
+type GeoJSONOption func(*geoJSONOptions)
+
+// WithGeoJSONIdentifiers sets the identifier types exported in each feature's properties. Only the MFL code is exported by default.
+func WithGeoJSONIdentifiers(identifierTypes ...FacilityIdentifierType) GeoJSONOption {
+	return func(o *geoJSONOptions) {
+		o.identifierTypes = identifierTypes
+	}
+}
+
+// newGeoJSONOptions applies the options to the defaults
+func newGeoJSONOptions(opts []GeoJSONOption) geoJSONOptions {
+	o := geoJSONOptions{identifierTypes: []FacilityIdentifierType{FacilityIdentifierTypeMFLCode}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// GeoJSONFeatureCollection is a GeoJSON (RFC 7946) feature collection of facilities
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+// GeoJSONFeature is a facility as a GeoJSON feature
+type GeoJSONFeature struct {
+	Type string `json:"type"`
+	ID   string `json:"id,omitempty"`
+	// Geometry is null for facilities without coordinates
+	Geometry   *GeoJSONPoint             `json:"geometry"`
+	Properties GeoJSONFacilityProperties `json:"properties"`
+}
+
+// GeoJSONPoint is a GeoJSON point. Its coordinates are longitude then latitude.
+type GeoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// GeoJSONFacilityProperties are the facility details exported with each feature
+type GeoJSONFacilityProperties struct {
+	Name         string         `json:"name"`
+	FacilityType string         `json:"facility_type,omitempty"`
+	County       string         `json:"county,omitempty"`
+	Status       FacilityStatus `json:"status,omitempty"`
+	// Distance is in kilometres, and only set when health CRM returned one
+	Distance    *float64          `json:"distance,omitempty"`
+	Services    []string          `json:"services,omitempty"`
+	Identifiers map[string]string `json:"identifiers,omitempty"`
+}
+
+// GeoJSONFeature converts the facility into a GeoJSON feature
+func (f FacilityOutput) GeoJSONFeature(opts ...GeoJSONOption) GeoJSONFeature {
+	return facilityFeature(f, newGeoJSONOptions(opts), time.Now())
+}
+
+// facilityFeature converts a facility into a GeoJSON feature, exporting the identifiers valid at now
+func facilityFeature(f FacilityOutput, o geoJSONOptions, now time.Time) GeoJSONFeature {
+	feature := GeoJSONFeature{
+		Type: "Feature",
+		ID:   f.ID,
+		Properties: GeoJSONFacilityProperties{
+			Name:         f.Name,
+			FacilityType: f.FacilityType,
+			County:       f.County,
+			Status:       f.Status,
+		},
+	}
+
+	if f.Coordinates != (CoordinatesOutput{}) {
+		feature.Geometry = &GeoJSONPoint{
+			Type:        "Point",
+			Coordinates: [2]float64{f.Coordinates.Longitude, f.Coordinates.Latitude},
+		}
+	}
+
+	if f.Distance != 0 {
+		distance := f.Distance
+		feature.Properties.Distance = &distance
+	}
+
+	for _, service := range f.Services {
+		feature.Properties.Services = append(feature.Properties.Services, service.Name)
+	}
+
+	for _, identifierType := range o.identifierTypes {
+		for _, identifier := range f.Identifiers {
+			if identifier.IdentifierType != identifierType.String() {
+				continue
+			}
+
+			// prefer the identifier in use today over expired ones
+			if _, ok := feature.Properties.Identifiers[identifier.IdentifierType]; ok && !identifier.IsValidAt(now) {
+				continue
+			}
+
+			if feature.Properties.Identifiers == nil {
+				feature.Properties.Identifiers = map[string]string{}
+			}
+
+			feature.Properties.Identifiers[identifier.IdentifierType] = identifier.IdentifierValue
+		}
+	}
+
+	return feature
+}
+
+// FacilitiesToGeoJSON converts facilities e.g a FacilityPage's Results into a GeoJSON feature collection
+func FacilitiesToGeoJSON(facilities []FacilityOutput, opts ...GeoJSONOption) *GeoJSONFeatureCollection {
+	o := newGeoJSONOptions(opts)
+	now := time.Now()
+
+	collection := &GeoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]GeoJSONFeature, 0, len(facilities)),
+	}
+
+	for _, facility := range facilities {
+		collection.Features = append(collection.Features, facilityFeature(facility, o, now))
+	}
+
+	return collection
+}
+
+// GeoJSONWriter streams facilities to a GeoJSON feature collection one feature at a time,
+// so that large exports are not held in memory. Close must be called to finish the collection.
+type GeoJSONWriter struct {
+	w       *bufio.Writer
+	opts    geoJSONOptions
+	count   int
+	started bool
+	closed  bool
+}
+
+// NewGeoJSONWriter returns a writer that writes a GeoJSON feature collection to w
+func NewGeoJSONWriter(w io.Writer, opts ...GeoJSONOption) *GeoJSONWriter {
+	return &GeoJSONWriter{
+		w:    bufio.NewWriter(w),
+		opts: newGeoJSONOptions(opts),
+	}
+}
+
+// Write adds a facility to the feature collection
+func (g *GeoJSONWriter) Write(facility FacilityOutput) error {
+	if g.closed {
+		return errors.New("GeoJSON writer is closed")
+	}
+
+	feature, err := json.Marshal(facilityFeature(facility, g.opts, time.Now()))
+	if err != nil {
+		return err
+	}
+
+	separator := ","
+	if !g.started {
+		separator = `{"type":"FeatureCollection","features":[`
+		g.started = true
+	}
+
+	if _, err := g.w.WriteString(separator); err != nil {
+		return err
+	}
+
+	if _, err := g.w.Write(feature); err != nil {
+		return err
+	}
+
+	g.count++
+
+	return nil
+}
+
+// Count returns the number of facilities written
+func (g *GeoJSONWriter) Count() int {
+	return g.count
+}
+
+// Close finishes the feature collection and flushes it. It does not close the underlying writer.
+func (g *GeoJSONWriter) Close() error {
+	if g.closed {
+		return nil
+	}
+
+	g.closed = true
+
+	end := "]}\n"
+	if !g.started {
+		end = `{"type":"FeatureCollection","features":[]}` + "\n"
+	}
+
+	if _, err := g.w.WriteString(end); err != nil {
+		return err
+	}
+
+	return g.w.Flush()
+}
+
+// WriteGeoJSON streams the facilities from an iterator e.g AllFacilities to w as a GeoJSON feature collection.
+// It returns the number of facilities written. On an iteration error the collection is left unfinished.
+func WriteGeoJSON(w io.Writer, facilities iter.Seq2[FacilityOutput, error], opts ...GeoJSONOption) (int, error) {
+	writer := NewGeoJSONWriter(w, opts...)
+
+	for facility, err := range facilities {
+		if err != nil {
+			return writer.Count(), err
+		}
+
+		if err := writer.Write(facility); err != nil {
+			return writer.Count(), err
+		}
+	}
+
+	return writer.Count(), writer.Close()
+}
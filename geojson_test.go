@@ -0,0 +1,212 @@
+package healthcrm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestFacilityOutput_GeoJSONFeature(t *testing.T) {
+	facility := FacilityOutput{
+		ID:           "123",
+		Name:         "Mbagathi",
+		FacilityType: "HOSPITAL",
+		County:       "Nairobi",
+		Status:       FacilityStatusPublished,
+		Coordinates:  CoordinatesOutput{Latitude: -1.30817, Longitude: 36.80373},
+		Distance:     2.5,
+		Services:     []FacilityService{{Name: "Maternity"}, {Name: "Dental"}},
+		Identifiers: []IdentifiersOutput{
+			{IdentifierType: "MFL_CODE", IdentifierValue: "13023"},
+			{IdentifierType: "SHA_SLADE_CODE", IdentifierValue: "SHA-OLD", ValidTo: "2024-06-30"},
+			{IdentifierType: "SHA_SLADE_CODE", IdentifierValue: "SHA-NEW", ValidFrom: "2024-07-01"},
+			{IdentifierType: "SHA_SLADE_CODE", IdentifierValue: "SHA-OLDER", ValidTo: "2023-06-30"},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		opts            []GeoJSONOption
+		wantIdentifiers map[string]string
+	}{
+		{
+			name:            "Happy case: MFL code by default",
+			wantIdentifiers: map[string]string{"MFL_CODE": "13023"},
+		},
+		{
+			name:            "Happy case: selected identifiers prefer the current one",
+			opts:            []GeoJSONOption{WithGeoJSONIdentifiers(FacilityIdentifierTypeSHASladeCode)},
+			wantIdentifiers: map[string]string{"SHA_SLADE_CODE": "SHA-NEW"},
+		},
+		{
+			name: "Happy case: no identifiers",
+			opts: []GeoJSONOption{WithGeoJSONIdentifiers()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feature := facility.GeoJSONFeature(tt.opts...)
+
+			if feature.Type != "Feature" || feature.ID != "123" {
+				t.Errorf("FacilityOutput.GeoJSONFeature() = %+v", feature)
+			}
+
+			if feature.Geometry == nil || feature.Geometry.Coordinates != [2]float64{36.80373, -1.30817} {
+				t.Errorf("FacilityOutput.GeoJSONFeature() geometry = %+v, want longitude first", feature.Geometry)
+			}
+
+			properties := feature.Properties
+			if properties.Name != "Mbagathi" || properties.Status != FacilityStatusPublished || *properties.Distance != 2.5 {
+				t.Errorf("FacilityOutput.GeoJSONFeature() properties = %+v", properties)
+			}
+
+			if !reflect.DeepEqual(properties.Services, []string{"Maternity", "Dental"}) {
+				t.Errorf("FacilityOutput.GeoJSONFeature() services = %v", properties.Services)
+			}
+
+			if !reflect.DeepEqual(properties.Identifiers, tt.wantIdentifiers) {
+				t.Errorf("FacilityOutput.GeoJSONFeature() identifiers = %v, want %v", properties.Identifiers, tt.wantIdentifiers)
+			}
+		})
+	}
+}
+
+func TestFacilitiesToGeoJSON(t *testing.T) {
+	page := &FacilityPage{
+		Results: []FacilityOutput{
+			{ID: "1", Name: "Mbagathi", Coordinates: CoordinatesOutput{Latitude: -1.3, Longitude: 36.8}},
+			{ID: "2", Name: "No location"},
+		},
+	}
+
+	data, err := json.Marshal(FacilitiesToGeoJSON(page.Results))
+	if err != nil {
+		t.Fatalf("unable to marshal GeoJSON: %v", err)
+	}
+
+	want := `{"type":"FeatureCollection","features":[` +
+		`{"type":"Feature","id":"1","geometry":{"type":"Point","coordinates":[36.8,-1.3]},"properties":{"name":"Mbagathi"}},` +
+		`{"type":"Feature","id":"2","geometry":null,"properties":{"name":"No location"}}]}`
+
+	if string(data) != want {
+		t.Errorf("FacilitiesToGeoJSON() = %s, want %s", data, want)
+	}
+
+	if data, _ := json.Marshal(FacilitiesToGeoJSON(nil)); string(data) != `{"type":"FeatureCollection","features":[]}` {
+		t.Errorf("FacilitiesToGeoJSON() = %s, want an empty collection", data)
+	}
+}
+
+func TestGeoJSONWriter(t *testing.T) {
+	facilities := []FacilityOutput{
+		{ID: "1", Name: "Mbagathi", Coordinates: CoordinatesOutput{Latitude: -1.3, Longitude: 36.8}},
+		{ID: "2", Name: "Kenyatta", Coordinates: CoordinatesOutput{Latitude: -1.301, Longitude: 36.807}},
+	}
+
+	tests := []struct {
+		name       string
+		facilities []FacilityOutput
+	}{
+		{name: "Happy case: facilities", facilities: facilities},
+		{name: "Happy case: no facilities"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			writer := NewGeoJSONWriter(&out)
+			for _, facility := range tt.facilities {
+				if err := writer.Write(facility); err != nil {
+					t.Fatalf("GeoJSONWriter.Write() error = %v", err)
+				}
+			}
+
+			if err := writer.Close(); err != nil {
+				t.Fatalf("GeoJSONWriter.Close() error = %v", err)
+			}
+
+			var streamed, want any
+			if err := json.Unmarshal(out.Bytes(), &streamed); err != nil {
+				t.Fatalf("GeoJSONWriter wrote invalid JSON %s: %v", out.String(), err)
+			}
+
+			wantData, _ := json.Marshal(FacilitiesToGeoJSON(tt.facilities))
+			_ = json.Unmarshal(wantData, &want)
+
+			if !reflect.DeepEqual(streamed, want) {
+				t.Errorf("GeoJSONWriter wrote %s, want %s", out.String(), wantData)
+			}
+
+			if writer.Count() != len(tt.facilities) {
+				t.Errorf("GeoJSONWriter.Count() = %d, want %d", writer.Count(), len(tt.facilities))
+			}
+
+			if err := writer.Write(FacilityOutput{}); err == nil {
+				t.Errorf("GeoJSONWriter.Write() expected an error after Close")
+			}
+		})
+	}
+}
+
+func TestWriteGeoJSON_IterationError(t *testing.T) {
+	failing := iter.Seq2[FacilityOutput, error](func(yield func(FacilityOutput, error) bool) {
+		if !yield(FacilityOutput{ID: "1", Name: "Mbagathi"}, nil) {
+			return
+		}
+
+		yield(FacilityOutput{}, errors.New("page 2 failed"))
+	})
+
+	count, err := WriteGeoJSON(&bytes.Buffer{}, failing)
+	if err == nil || count != 1 {
+		t.Errorf("WriteGeoJSON() = %d, %v, want 1 and an error", count, err)
+	}
+}
+
+func TestHealthCRMLib_WriteGeoJSON(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	MockAuthenticate()
+
+	registerPagedResponder("/v1/facilities/facilities/", func(page string) string {
+		return fmt.Sprintf(`{"id": "facility-%s", "name": "Clinic %s", "county": "Kiambu", "coordinates": {"latitude": -1.1, "longitude": 36.9}}`, page, page)
+	})
+
+	h, err := NewHealthCRMLib(testOptions(baseURL)...)
+	if err != nil {
+		t.Fatalf("unable to initialize sdk: %v", err)
+	}
+	defer h.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var out bytes.Buffer
+
+	count, err := WriteGeoJSON(&out, h.AllFacilities(ctx, FilterFacilitiesInput{CrmServiceCode: "05", SearchParameter: "Kiambu"}))
+	if err != nil {
+		t.Fatalf("WriteGeoJSON() error = %v", err)
+	}
+
+	var collection GeoJSONFeatureCollection
+	if err := json.Unmarshal(out.Bytes(), &collection); err != nil {
+		t.Fatalf("WriteGeoJSON() wrote invalid JSON: %v", err)
+	}
+
+	if count != 2 || len(collection.Features) != 2 || collection.Features[1].ID != "facility-2" {
+		t.Errorf("WriteGeoJSON() = %d, %s", count, out.String())
+	}
+
+	if got := httpmock.GetCallCountInfo()[http.MethodGet+" "+baseURL+"/v1/facilities/facilities/"]; got != 2 {
+		t.Errorf("WriteGeoJSON() fetched %d pages, want 2", got)
+	}
+}
@@ -0,0 +1,131 @@
+package healthcrm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrAuthFailed is returned by Healthy when the last attempt to refresh the access token failed
+	ErrAuthFailed = errors.New("health CRM authentication failed")
+
+	// ErrTokenExpired is returned by Healthy when the access token has expired and has not been renewed
+	ErrTokenExpired = errors.New("health CRM access token has expired")
+
+	// ErrUnreachable is returned by Healthy when health CRM's base URL cannot be reached
+	ErrUnreachable = errors.New("health CRM is unreachable")
+)
+
+// HealthStatus reports the state of the library's authentication with health CRM
+type HealthStatus struct {
+	Closed           bool
+	AuthFailed       bool
+	LastRefresh      time.Time
+	LastRefreshError error
+	TokenExpiry      time.Time
+}
+
+// readinessResponse is the JSON body written by the readiness handler
+type readinessResponse struct {
+	Ready            bool       `json:"ready"`
+	Error            string     `json:"error,omitempty"`
+	AuthFailed       bool       `json:"auth_failed"`
+	LastRefresh      time.Time  `json:"last_refresh"`
+	LastRefreshError string     `json:"last_refresh_error,omitempty"`
+	TokenExpiry      *time.Time `json:"token_expiry,omitempty"`
+}
+
+// status returns a snapshot of the client's authentication state
+func (c *client) status() HealthStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return HealthStatus{
+		Closed:           c.closed.Load(),
+		AuthFailed:       c.authFailed,
+		LastRefresh:      c.lastRefresh,
+		LastRefreshError: c.lastRefreshErr,
+		TokenExpiry:      c.tokenExpiry,
+	}
+}
+
+// ping checks that health CRM can be reached. Any HTTP response, whatever its status, counts as reachable.
+func (c *client) ping(ctx context.Context) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodHead, c.baseURL+"/", nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnreachable, err)
+	}
+
+	_, _ = io.Copy(io.Discard, response.Body)
+	response.Body.Close()
+
+	return nil
+}
+
+// Status returns the library's authentication state without making any network calls
+func (h *HealthCRMLib) Status() HealthStatus {
+	return h.client.status()
+}
+
+// Healthy reports whether the library can currently make requests to health CRM.
+// It checks the authentication state and that the base URL is reachable.
+func (h *HealthCRMLib) Healthy(ctx context.Context) error {
+	status := h.Status()
+
+	switch {
+	case status.Closed:
+		return ErrClientClosed
+
+	case status.AuthFailed:
+		return fmt.Errorf("%w: %w", ErrAuthFailed, status.LastRefreshError)
+
+	case !status.TokenExpiry.IsZero() && time.Now().After(status.TokenExpiry):
+		return ErrTokenExpired
+	}
+
+	return h.client.ping(ctx)
+}
+
+// ReadinessHandler returns a http.Handler suitable for readiness probes.
+// It responds with 200 OK when Healthy succeeds and 503 Service Unavailable otherwise,
+// with the current HealthStatus as the JSON body.
+func (h *HealthCRMLib) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		statusCode := http.StatusOK
+		err := h.Healthy(r.Context())
+		status := h.Status()
+
+		body := readinessResponse{
+			Ready:       err == nil,
+			AuthFailed:  status.AuthFailed,
+			LastRefresh: status.LastRefresh,
+		}
+
+		if err != nil {
+			statusCode = http.StatusServiceUnavailable
+			body.Error = err.Error()
+		}
+
+		if status.LastRefreshError != nil {
+			body.LastRefreshError = status.LastRefreshError.Error()
+		}
+
+		if !status.TokenExpiry.IsZero() {
+			body.TokenExpiry = &status.TokenExpiry
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		_ = json.NewEncoder(w).Encode(body)
+	})
+}
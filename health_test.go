@@ -0,0 +1,180 @@
+package healthcrm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestHealthCRMLib_Healthy(t *testing.T) {
+	tests := []struct {
+		name      string
+		setup     func(h *HealthCRMLib)
+		reachable bool
+		wantErr   error
+	}{
+		{
+			name:      "Happy case: authenticated and reachable",
+			setup:     func(h *HealthCRMLib) {},
+			reachable: true,
+			wantErr:   nil,
+		},
+		{
+			name: "Sad case: token refresh failed",
+			setup: func(h *HealthCRMLib) {
+				h.client.setRefreshResult(errors.New("invalid_grant"))
+			},
+			reachable: true,
+			wantErr:   ErrAuthFailed,
+		},
+		{
+			name: "Sad case: token expired",
+			setup: func(h *HealthCRMLib) {
+				h.client.mu.Lock()
+				h.client.tokenExpiry = time.Now().Add(-time.Minute)
+				h.client.mu.Unlock()
+			},
+			reachable: true,
+			wantErr:   ErrTokenExpired,
+		},
+		{
+			name:      "Sad case: health CRM unreachable",
+			setup:     func(h *HealthCRMLib) {},
+			reachable: false,
+			wantErr:   ErrUnreachable,
+		},
+		{
+			name: "Sad case: closed",
+			setup: func(h *HealthCRMLib) {
+				_ = h.Close()
+			},
+			reachable: true,
+			wantErr:   ErrClientClosed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			MockAuthenticate()
+
+			if tt.reachable {
+				httpmock.RegisterResponder(http.MethodHead, fmt.Sprintf("%s/", baseURL), httpmock.NewStringResponder(http.StatusNotFound, ""))
+			}
+
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
+			if err != nil {
+				t.Fatalf("unable to initialize sdk: %v", err)
+			}
+			defer h.Close()
+
+			tt.setup(h)
+
+			err = h.Healthy(context.Background())
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("HealthCRMLib.Healthy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("HealthCRMLib.Healthy() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHealthCRMLib_Status(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	MockAuthenticate()
+
+	h, err := NewHealthCRMLib(testOptions(baseURL)...)
+	if err != nil {
+		t.Fatalf("unable to initialize sdk: %v", err)
+	}
+	defer h.Close()
+
+	status := h.Status()
+	if status.AuthFailed || status.LastRefreshError != nil {
+		t.Errorf("HealthCRMLib.Status() = %+v, want a successful authentication", status)
+	}
+
+	if status.LastRefresh.IsZero() {
+		t.Errorf("HealthCRMLib.Status() expected the last refresh time to be set")
+	}
+
+	refreshErr := errors.New("invalid_grant")
+	h.client.setRefreshResult(refreshErr)
+
+	status = h.Status()
+	if !status.AuthFailed || !errors.Is(status.LastRefreshError, refreshErr) {
+		t.Errorf("HealthCRMLib.Status() = %+v, want the failed refresh to be reported", status)
+	}
+}
+
+func TestHealthCRMLib_ReadinessHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		authErr    error
+		wantStatus int
+		wantReady  bool
+	}{
+		{
+			name:       "Happy case: ready",
+			wantStatus: http.StatusOK,
+			wantReady:  true,
+		},
+		{
+			name:       "Sad case: not ready",
+			authErr:    errors.New("invalid_grant"),
+			wantStatus: http.StatusServiceUnavailable,
+			wantReady:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			MockAuthenticate()
+			httpmock.RegisterResponder(http.MethodHead, fmt.Sprintf("%s/", baseURL), httpmock.NewStringResponder(http.StatusOK, ""))
+
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
+			if err != nil {
+				t.Fatalf("unable to initialize sdk: %v", err)
+			}
+			defer h.Close()
+
+			if tt.authErr != nil {
+				h.client.setRefreshResult(tt.authErr)
+			}
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+
+			h.ReadinessHandler().ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("ReadinessHandler() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+
+			var body readinessResponse
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatalf("unable to decode readiness response: %v", err)
+			}
+
+			if body.Ready != tt.wantReady {
+				t.Errorf("ReadinessHandler() ready = %v, want %v", body.Ready, tt.wantReady)
+			}
+
+			if tt.authErr != nil && body.LastRefreshError != tt.authErr.Error() {
+				t.Errorf("ReadinessHandler() last_refresh_error = %q, want %q", body.LastRefreshError, tt.authErr.Error())
+			}
+		})
+	}
+}
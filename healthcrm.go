@@ -8,13 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-
-	"github.com/savannahghi/serverutils"
-)
-
-var (
-	// BaseURL represents the health CRM's base URL
-	BaseURL = serverutils.MustGetEnvVar("HEALTH_CRM_BASE_URL")
+	"time"
 )
 
 const (
@@ -27,8 +21,26 @@ type HealthCRMLib struct {
 }
 
 // NewHealthCRMLib initializes a new instance of healthCRM SDK
-func NewHealthCRMLib() (*HealthCRMLib, error) {
-	client, err := newClient()
+//
+// The instance is configured through options e.g
+//
+//	NewHealthCRMLib(WithBaseURL("https://healthcrm.example.com"), WithAuthServerEndpoint(...), WithCredentials(...), WithGrantType("password"))
+//
+// or from the HEALTH_CRM_* environment variables using NewHealthCRMLib(WithEnvConfig())
+func NewHealthCRMLib(opts ...Option) (*HealthCRMLib, error) {
+	cfg := defaultOptions()
+
+	for _, opt := range opts {
+		if err := opt(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	client, err := newClient(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -38,6 +50,28 @@ func NewHealthCRMLib() (*HealthCRMLib, error) {
 	}, nil
 }
 
+// Close stops the background token refresher and releases idle HTTP connections.
+// Any call made on the library after Close returns ErrClientClosed.
+func (h *HealthCRMLib) Close() error {
+	return h.client.close(context.Background())
+}
+
+// Shutdown is like Close but stops waiting for the background token refresher to exit once ctx is done
+func (h *HealthCRMLib) Shutdown(ctx context.Context) error {
+	return h.client.close(ctx)
+}
+
+// TokenExpiry returns the time at which the current access token expires.
+// The zero time is returned when the auth server did not report the token's lifetime.
+func (h *HealthCRMLib) TokenExpiry() time.Time {
+	return h.client.getTokenExpiry()
+}
+
+// RateLimitStats reports how long requests have waited on the client side rate limits
+func (h *HealthCRMLib) RateLimitStats() RateLimitStats {
+	return h.client.rateLimiter.stats()
+}
+
 // CreateFacility is used to create facility in health CRM service
 func (h *HealthCRMLib) CreateFacility(ctx context.Context, facility *Facility) (*FacilityOutput, error) {
 	path := "/v1/facilities/facilities/"
@@ -54,7 +88,7 @@ func (h *HealthCRMLib) CreateFacility(ctx context.Context, facility *Facility) (
 	}
 
 	if response.StatusCode != http.StatusCreated {
-		return nil, errors.New(string(respBytes))
+		return nil, newResponseError(response, respBytes)
 	}
 
 	var facilityResponse *FacilityOutput
@@ -83,7 +117,7 @@ func (h *HealthCRMLib) GetFacilityByID(ctx context.Context, id string) (*Facilit
 	}
 
 	if response.StatusCode != http.StatusOK {
-		return nil, errors.New(string(respBytes))
+		return nil, newResponseError(response, respBytes)
 	}
 
 	var facilityOutput *FacilityOutput
@@ -112,7 +146,63 @@ func (h *HealthCRMLib) UpdateFacility(ctx context.Context, id string, updatePayl
 	}
 
 	if response.StatusCode != http.StatusOK {
-		return nil, errors.New(string(respBytes))
+		return nil, newResponseError(response, respBytes)
+	}
+
+	var facilityOutput *FacilityOutput
+
+	err = json.Unmarshal(respBytes, &facilityOutput)
+	if err != nil {
+		return nil, err
+	}
+
+	return facilityOutput, nil
+}
+
+// DeleteFacility is used to permanently remove a facility from health CRM.
+// Facilities that have closed should be deactivated instead so that their records are kept.
+func (h *HealthCRMLib) DeleteFacility(ctx context.Context, id string) error {
+	path := fmt.Sprintf("/v1/facilities/facilities/%s/", id)
+	response, err := h.client.MakeRequest(ctx, http.MethodDelete, path, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	respBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("could not read response: %w", err)
+	}
+
+	if response.StatusCode != http.StatusNoContent && response.StatusCode != http.StatusOK {
+		return newResponseError(response, respBytes)
+	}
+
+	return nil
+}
+
+// UpdateFacilityStatus is used to move a facility to a new status
+func (h *HealthCRMLib) UpdateFacilityStatus(ctx context.Context, id string, status FacilityStatus) (*FacilityOutput, error) {
+	if !status.IsValid() {
+		return nil, fmt.Errorf("invalid facility status: %s", status)
+	}
+
+	path := fmt.Sprintf("/v1/facilities/facilities/%s/", id)
+	response, err := h.client.MakeRequest(ctx, http.MethodPatch, path, nil, facilityStatusInput{Status: status})
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	respBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read response: %w", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, newResponseError(response, respBytes)
 	}
 
 	var facilityOutput *FacilityOutput
@@ -125,6 +215,17 @@ func (h *HealthCRMLib) UpdateFacility(ctx context.Context, id string, updatePayl
 	return facilityOutput, nil
 }
 
+// DeactivateFacility is used to retire a facility e.g a clinic that has closed.
+// The facility is kept in health CRM but is no longer listed.
+func (h *HealthCRMLib) DeactivateFacility(ctx context.Context, id string) (*FacilityOutput, error) {
+	return h.UpdateFacilityStatus(ctx, id, FacilityStatusInactive)
+}
+
+// ReactivateFacility is used to publish a facility that was deactivated
+func (h *HealthCRMLib) ReactivateFacility(ctx context.Context, id string) (*FacilityOutput, error) {
+	return h.UpdateFacilityStatus(ctx, id, FacilityStatusPublished)
+}
+
 // GetServices retrieves a list of healthcare services provided by facilities
 // that are owned by a specific SIL service, such as Mycarehub or Advantage.
 func (h *HealthCRMLib) GetServices(ctx context.Context, pagination *Pagination, crmServiceCode string) (*FacilityServicePage, error) {
@@ -132,9 +233,8 @@ func (h *HealthCRMLib) GetServices(ctx context.Context, pagination *Pagination,
 
 	queryParams := url.Values{}
 
-	if pagination != nil {
-		queryParams.Add("page_size", pagination.PageSize)
-		queryParams.Add("page", pagination.Page)
+	if err := pagination.addTo(queryParams); err != nil {
+		return nil, err
 	}
 
 	queryParams.Add("crm_service_code", crmServiceCode)
@@ -152,7 +252,7 @@ func (h *HealthCRMLib) GetServices(ctx context.Context, pagination *Pagination,
 	}
 
 	if response.StatusCode != http.StatusOK {
-		return nil, errors.New(string(respBytes))
+		return nil, newResponseError(response, respBytes)
 	}
 
 	var facilityServicePage FacilityServicePage
@@ -174,9 +274,8 @@ func (h *HealthCRMLib) GetPractitioners(ctx context.Context, filters FilterPract
 		return nil, errors.New("CRM service code must be provided")
 	}
 
-	if filters.Pagination != nil {
-		queryParams.Add("page_size", filters.Pagination.PageSize)
-		queryParams.Add("page", filters.Pagination.Page)
+	if err := filters.Pagination.addTo(queryParams); err != nil {
+		return nil, err
 	}
 
 	if len(filters.Specialty) > 0 && filters.SearchParameter != "" {
@@ -223,7 +322,7 @@ func (h *HealthCRMLib) GetPractitioners(ctx context.Context, filters FilterPract
 	}
 
 	if response.StatusCode != http.StatusOK {
-		return nil, errors.New(string(respBytes))
+		return nil, newResponseError(response, respBytes)
 	}
 
 	var practitioners Practitioners
@@ -251,7 +350,7 @@ func (h *HealthCRMLib) GetPractitionerByID(ctx context.Context, practitionerID s
 	}
 
 	if response.StatusCode != http.StatusOK {
-		return nil, errors.New(string(respBytes))
+		return nil, newResponseError(response, respBytes)
 	}
 
 	var practitioner *Practitioner
@@ -270,9 +369,8 @@ func (h *HealthCRMLib) GetSpecialties(ctx context.Context, pagination *Paginatio
 
 	queryParams := url.Values{}
 
-	if pagination != nil {
-		queryParams.Add("page_size", pagination.PageSize)
-		queryParams.Add("page", pagination.Page)
+	if err := pagination.addTo(queryParams); err != nil {
+		return nil, err
 	}
 
 	queryParams.Add("crm_service_code", crmServiceCode)
@@ -290,7 +388,7 @@ func (h *HealthCRMLib) GetSpecialties(ctx context.Context, pagination *Paginatio
 	}
 
 	if response.StatusCode != http.StatusOK {
-		return nil, errors.New(string(respBytes))
+		return nil, newResponseError(response, respBytes)
 	}
 
 	var specialties Specialties
@@ -310,9 +408,8 @@ func (h *HealthCRMLib) GetFacilitiesOfferingAService(ctx context.Context, servic
 	queryParams := url.Values{}
 	queryParams.Add("service", serviceID)
 
-	if pagination != nil {
-		queryParams.Add("page_size", pagination.PageSize)
-		queryParams.Add("page", pagination.Page)
+	if err := pagination.addTo(queryParams); err != nil {
+		return nil, err
 	}
 
 	response, err := h.client.MakeRequest(ctx, http.MethodGet, path, queryParams, nil)
@@ -328,7 +425,7 @@ func (h *HealthCRMLib) GetFacilitiesOfferingAService(ctx context.Context, servic
 	}
 
 	if response.StatusCode != http.StatusOK {
-		return nil, errors.New(string(respBytes))
+		return nil, newResponseError(response, respBytes)
 	}
 
 	var output *FacilityPage
@@ -358,7 +455,7 @@ func (h *HealthCRMLib) CreateService(ctx context.Context, input FacilityServiceI
 	}
 
 	if response.StatusCode != http.StatusOK {
-		return nil, errors.New(string(respBytes))
+		return nil, newResponseError(response, respBytes)
 	}
 
 	var output *FacilityService
@@ -388,7 +485,7 @@ func (h *HealthCRMLib) LinkServiceToFacility(ctx context.Context, facilityID str
 	}
 
 	if response.StatusCode != http.StatusCreated {
-		return nil, errors.New(string(respBytes))
+		return nil, newResponseError(response, respBytes)
 	}
 
 	var output *FacilityService
@@ -441,22 +538,12 @@ func (h *HealthCRMLib) GetFacilities(ctx context.Context, filters FilterFaciliti
 		return nil, errors.New("CRM service code must be provided")
 	}
 
-	if pagination != nil {
-		queryParams.Add("page_size", pagination.PageSize)
-		queryParams.Add("page", pagination.Page)
+	if err := pagination.addTo(queryParams); err != nil {
+		return nil, err
 	}
 
-	if location != nil {
-		coordinateString, err := location.ToString()
-		if err != nil {
-			return nil, err
-		}
-
-		queryParams.Add("ref_location", coordinateString)
-
-		if location.Radius != "" {
-			queryParams.Add("distance", location.Radius)
-		}
+	if err := addLocationFilter(queryParams, location, filters.Near, filters.Radius); err != nil {
+		return nil, err
 	}
 
 	if len(serviceIDs) > 0 && searchParameter != "" {
@@ -499,8 +586,7 @@ func (h *HealthCRMLib) GetFacilities(ctx context.Context, filters FilterFaciliti
 	}
 
 	if response.StatusCode != http.StatusOK {
-		// TODO: Get the exact error message (should be formatted well)
-		return nil, errors.New(string(respBytes))
+		return nil, newResponseError(response, respBytes)
 	}
 
 	var facilityPage *FacilityPage
@@ -530,7 +616,7 @@ func (h *HealthCRMLib) GetService(ctx context.Context, serviceID string) (*Facil
 	}
 
 	if response.StatusCode != http.StatusOK {
-		return nil, errors.New(string(respBytes))
+		return nil, newResponseError(response, respBytes)
 	}
 
 	var service FacilityService
@@ -558,7 +644,7 @@ func (h *HealthCRMLib) CreateProfile(ctx context.Context, profile *ProfileInput)
 	}
 
 	if response.StatusCode != http.StatusAccepted {
-		return nil, errors.New(string(respBytes))
+		return nil, newResponseError(response, respBytes)
 	}
 
 	var profileResponse *ProfileOutput
@@ -587,7 +673,7 @@ func (h *HealthCRMLib) MatchProfile(ctx context.Context, profile *ProfileInput)
 	}
 
 	if response.StatusCode != http.StatusOK {
-		return nil, errors.New(string(respBytes))
+		return nil, newResponseError(response, respBytes)
 	}
 
 	var profileResponse *ProfileOutput
@@ -637,7 +723,7 @@ func (h *HealthCRMLib) GetMultipleServices(ctx context.Context, servicesIDs []st
 	}
 
 	if response.StatusCode != http.StatusOK {
-		return nil, errors.New(string(respBytes))
+		return nil, newResponseError(response, respBytes)
 	}
 
 	var services *FacilityServices
@@ -686,7 +772,7 @@ func (h *HealthCRMLib) GetMultipleFacilities(ctx context.Context, facilityIDs []
 	}
 
 	if response.StatusCode != http.StatusOK {
-		return nil, errors.New(string(respBytes))
+		return nil, newResponseError(response, respBytes)
 	}
 
 	var facilities *FacilityOutputs
@@ -730,7 +816,7 @@ func (h *HealthCRMLib) GetPersonIdentifiers(ctx context.Context, healthID string
 	}
 
 	if response.StatusCode != http.StatusOK {
-		return nil, errors.New(string(respBytes))
+		return nil, newResponseError(response, respBytes)
 	}
 
 	var identifiers *ProfileIdentifierOutputs
@@ -763,7 +849,7 @@ func (h *HealthCRMLib) GetPersonContacts(ctx context.Context, healthID string) (
 	}
 
 	if response.StatusCode != http.StatusOK {
-		return nil, errors.New(string(respBytes))
+		return nil, newResponseError(response, respBytes)
 	}
 
 	var identifiers *ProfileContactOutputs
@@ -791,7 +877,7 @@ func (h *HealthCRMLib) VerifyIdentifierDocument(ctx context.Context, input IDVer
 	}
 
 	if response.StatusCode != http.StatusOK {
-		return nil, errors.New(string(respBytes))
+		return nil, newResponseError(response, respBytes)
 	}
 
 	var result IDVerificationResult
@@ -2,9 +2,12 @@ package healthcrm
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/brianvoe/gofakeit"
 	"github.com/google/uuid"
@@ -12,12 +15,14 @@ import (
 	"github.com/savannahghi/authutils"
 	"github.com/savannahghi/enumutils"
 	"github.com/savannahghi/scalarutils"
-	"github.com/savannahghi/serverutils"
 )
 
+// baseURL is the health CRM base URL of the library instances built by the tests
+const baseURL = "https://healthcrm.test"
+
 // MockAuthenticate mocks a mock login request to obtain a token
 func MockAuthenticate() {
-	httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", serverutils.MustGetEnvVar("HEALTH_CRM_AUTH_SERVER_ENDPOINT")), func(r *http.Request) (*http.Response, error) {
+	httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", testAuthServerEndpoint), func(r *http.Request) (*http.Response, error) {
 		resp := authutils.OAUTHResponse{
 			Scope:        "",
 			ExpiresIn:    3600,
@@ -100,7 +105,7 @@ func TestHealthCRMLib_CreateFacility(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.name == "Happy case: create facility" {
-				path := fmt.Sprintf("%s/v1/facilities/facilities/", BaseURL)
+				path := fmt.Sprintf("%s/v1/facilities/facilities/", baseURL)
 				httpmock.RegisterResponder(http.MethodPost, path, func(r *http.Request) (*http.Response, error) {
 					resp := &FacilityOutput{
 						ID:           gofakeit.UUID(),
@@ -130,7 +135,7 @@ func TestHealthCRMLib_CreateFacility(t *testing.T) {
 				})
 			}
 			if tt.name == "Sad case: unable to create facility" {
-				path := fmt.Sprintf("%s/v1/facilities/facilities/", BaseURL)
+				path := fmt.Sprintf("%s/v1/facilities/facilities/", baseURL)
 				httpmock.RegisterResponder(http.MethodPost, path, func(r *http.Request) (*http.Response, error) {
 					resp := &Facility{
 						ID:            gofakeit.UUID(),
@@ -149,7 +154,7 @@ func TestHealthCRMLib_CreateFacility(t *testing.T) {
 				})
 			}
 			if tt.name == "Sad case: unable to make request" {
-				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", serverutils.MustGetEnvVar("HEALTH_CRM_AUTH_SERVER_ENDPOINT")), func(r *http.Request) (*http.Response, error) {
+				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", testAuthServerEndpoint), func(r *http.Request) (*http.Response, error) {
 					resp := authutils.OAUTHResponse{
 						Scope:        "",
 						ExpiresIn:    3600,
@@ -164,7 +169,7 @@ func TestHealthCRMLib_CreateFacility(t *testing.T) {
 			httpmock.Activate()
 			defer httpmock.DeactivateAndReset()
 			MockAuthenticate()
-			h, err := NewHealthCRMLib()
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
 			if err != nil {
 				t.Errorf("unable to initialize sdk: %v", err)
 			}
@@ -197,8 +202,8 @@ func TestHealthCRMLib_GetFacilities(t *testing.T) {
 				},
 					ServiceIDs: []string{"1234"},
 					Pagination: &Pagination{
-						Page:     "1",
-						PageSize: "10",
+						Page:     1,
+						PageSize: 10,
 					},
 					CrmServiceCode: "05",
 				},
@@ -215,8 +220,8 @@ func TestHealthCRMLib_GetFacilities(t *testing.T) {
 				},
 					ServiceIDs: []string{"1234", "4567"},
 					Pagination: &Pagination{
-						Page:     "1",
-						PageSize: "10",
+						Page:     1,
+						PageSize: 10,
 					},
 					CrmServiceCode: "05",
 				},
@@ -234,8 +239,8 @@ func TestHealthCRMLib_GetFacilities(t *testing.T) {
 					},
 					SearchParameter: "prep",
 					Pagination: &Pagination{
-						Page:     "1",
-						PageSize: "10",
+						Page:     1,
+						PageSize: 10,
 					},
 					CrmServiceCode: "05",
 				},
@@ -267,8 +272,8 @@ func TestHealthCRMLib_GetFacilities(t *testing.T) {
 					},
 					SearchParameter: "prep",
 					Pagination: &Pagination{
-						Page:     "1",
-						PageSize: "10",
+						Page:     1,
+						PageSize: 10,
 					},
 				},
 			},
@@ -285,8 +290,8 @@ func TestHealthCRMLib_GetFacilities(t *testing.T) {
 					},
 					SearchParameter: "prep",
 					Pagination: &Pagination{
-						Page:     "1",
-						PageSize: "10",
+						Page:     1,
+						PageSize: 10,
 					},
 					CrmServiceCode: "05",
 					IdentifierType: FacilityIdentifierTypeMFLCode,
@@ -309,7 +314,7 @@ func TestHealthCRMLib_GetFacilities(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.name == "Happy case: fetch facility(ies)" {
-				path := fmt.Sprintf("%s/v1/facilities/facilities/", BaseURL)
+				path := fmt.Sprintf("%s/v1/facilities/facilities/", baseURL)
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
 					resp := &FacilityOutput{
 						ID:           gofakeit.UUID(),
@@ -339,7 +344,7 @@ func TestHealthCRMLib_GetFacilities(t *testing.T) {
 				})
 			}
 			if tt.name == "Happy case: fetch facilities" {
-				path := fmt.Sprintf("%s/v1/facilities/facilities/", BaseURL)
+				path := fmt.Sprintf("%s/v1/facilities/facilities/", baseURL)
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
 					service1 := &FacilityOutput{
 						ID:           gofakeit.UUID(),
@@ -399,7 +404,7 @@ func TestHealthCRMLib_GetFacilities(t *testing.T) {
 			}
 
 			if tt.name == "Happy case: search facility by service name" {
-				path := fmt.Sprintf("%s/v1/facilities/facilities/", BaseURL)
+				path := fmt.Sprintf("%s/v1/facilities/facilities/", baseURL)
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
 					resp := &FacilityOutput{
 						ID:           gofakeit.UUID(),
@@ -430,14 +435,14 @@ func TestHealthCRMLib_GetFacilities(t *testing.T) {
 			}
 
 			if tt.name == "Sad case: unable to fetch facility(ies)" {
-				path := fmt.Sprintf("%s/v1/facilities/facilities/", BaseURL)
+				path := fmt.Sprintf("%s/v1/facilities/facilities/", baseURL)
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
 					return httpmock.NewJsonResponse(http.StatusBadRequest, nil)
 				})
 			}
 
 			if tt.name == "Sad case: unable to make request" {
-				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", serverutils.MustGetEnvVar("HEALTH_CRM_AUTH_SERVER_ENDPOINT")), func(r *http.Request) (*http.Response, error) {
+				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", testAuthServerEndpoint), func(r *http.Request) (*http.Response, error) {
 					resp := authutils.OAUTHResponse{
 						Scope:        "",
 						ExpiresIn:    3600,
@@ -452,7 +457,7 @@ func TestHealthCRMLib_GetFacilities(t *testing.T) {
 			httpmock.Activate()
 			defer httpmock.DeactivateAndReset()
 			MockAuthenticate()
-			h, err := NewHealthCRMLib()
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
 			if err != nil {
 				t.Errorf("unable to initialize sdk: %v", err)
 			}
@@ -504,7 +509,7 @@ func TestHealthCRMLib_GetFacilityByID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.name == "Happy case: get facility" {
-				path := fmt.Sprintf("%s/v1/facilities/facilities/%s/", BaseURL, "123")
+				path := fmt.Sprintf("%s/v1/facilities/facilities/%s/", baseURL, "123")
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
 					resp := &FacilityOutput{
 						ID:           gofakeit.UUID(),
@@ -535,14 +540,14 @@ func TestHealthCRMLib_GetFacilityByID(t *testing.T) {
 			}
 
 			if tt.name == "Sad case: unable to get facility" {
-				path := fmt.Sprintf("%s/v1/facilities/facilities/%s/", BaseURL, "123")
+				path := fmt.Sprintf("%s/v1/facilities/facilities/%s/", baseURL, "123")
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
 					return httpmock.NewJsonResponse(http.StatusBadRequest, nil)
 				})
 			}
 
 			if tt.name == "Sad case: unable to make request" {
-				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", serverutils.MustGetEnvVar("HEALTH_CRM_AUTH_SERVER_ENDPOINT")), func(r *http.Request) (*http.Response, error) {
+				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", testAuthServerEndpoint), func(r *http.Request) (*http.Response, error) {
 					resp := authutils.OAUTHResponse{
 						Scope:        "",
 						ExpiresIn:    3600,
@@ -557,7 +562,7 @@ func TestHealthCRMLib_GetFacilityByID(t *testing.T) {
 			httpmock.Activate()
 			defer httpmock.DeactivateAndReset()
 			MockAuthenticate()
-			h, err := NewHealthCRMLib()
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
 			if err != nil {
 				t.Errorf("unable to initialize sdk: %v", err)
 			}
@@ -619,7 +624,7 @@ func TestHealthCRMLib_UpdateFacility(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.name == "Happy case: update facility" {
-				path := fmt.Sprintf("%s/v1/facilities/facilities/%s/", BaseURL, "123")
+				path := fmt.Sprintf("%s/v1/facilities/facilities/%s/", baseURL, "123")
 				httpmock.RegisterResponder(http.MethodPatch, path, func(r *http.Request) (*http.Response, error) {
 					resp := &FacilityOutput{
 						ID:           gofakeit.UUID(),
@@ -649,14 +654,14 @@ func TestHealthCRMLib_UpdateFacility(t *testing.T) {
 				})
 			}
 			if tt.name == "Sad case: unable to update facility" {
-				path := fmt.Sprintf("%s/v1/facilities/facilities/%s/", BaseURL, "123")
+				path := fmt.Sprintf("%s/v1/facilities/facilities/%s/", baseURL, "123")
 				httpmock.RegisterResponder(http.MethodPatch, path, func(r *http.Request) (*http.Response, error) {
 					return httpmock.NewJsonResponse(http.StatusBadGateway, nil)
 				})
 			}
 
 			if tt.name == "Sad case: unable to make request" {
-				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", serverutils.MustGetEnvVar("HEALTH_CRM_AUTH_SERVER_ENDPOINT")), func(r *http.Request) (*http.Response, error) {
+				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", testAuthServerEndpoint), func(r *http.Request) (*http.Response, error) {
 					resp := authutils.OAUTHResponse{
 						Scope:        "",
 						ExpiresIn:    3600,
@@ -671,7 +676,7 @@ func TestHealthCRMLib_UpdateFacility(t *testing.T) {
 			httpmock.Activate()
 			defer httpmock.DeactivateAndReset()
 			MockAuthenticate()
-			h, err := NewHealthCRMLib()
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
 			if err != nil {
 				t.Errorf("unable to initialize sdk: %v", err)
 			}
@@ -702,8 +707,8 @@ func TestHealthCRMLib_GetServices(t *testing.T) {
 			args: args{
 				ctx: context.Background(),
 				pagination: &Pagination{
-					Page:     "2",
-					PageSize: "5",
+					Page:     2,
+					PageSize: 5,
 				},
 				crmServiceCode: "05",
 			},
@@ -736,7 +741,7 @@ func TestHealthCRMLib_GetServices(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.name == "Happy case: get all services" {
-				path := fmt.Sprintf("%s/v1/facilities/services/", BaseURL)
+				path := fmt.Sprintf("%s/v1/facilities/services/", baseURL)
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
 					resp := &FacilityServicePage{
 						Results: []FacilityService{
@@ -753,19 +758,19 @@ func TestHealthCRMLib_GetServices(t *testing.T) {
 			}
 
 			if tt.name == "Sad case: unable to get all services" {
-				path := fmt.Sprintf("%s/v1/facilities/services/", BaseURL)
+				path := fmt.Sprintf("%s/v1/facilities/services/", baseURL)
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
 					return httpmock.NewJsonResponse(http.StatusBadGateway, nil)
 				})
 			}
 			if tt.name == "Sad case: unable to get facility services" {
-				path := fmt.Sprintf("%s/v1/facilities/services/?facility=1b5baf1a-1aec-48bd-951c-01896e5fe5a8", BaseURL)
+				path := fmt.Sprintf("%s/v1/facilities/services/?facility=1b5baf1a-1aec-48bd-951c-01896e5fe5a8", baseURL)
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
 					return httpmock.NewJsonResponse(http.StatusBadGateway, nil)
 				})
 			}
 			if tt.name == "Sad case: unable to make request" {
-				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", serverutils.MustGetEnvVar("HEALTH_CRM_AUTH_SERVER_ENDPOINT")), func(r *http.Request) (*http.Response, error) {
+				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", testAuthServerEndpoint), func(r *http.Request) (*http.Response, error) {
 					resp := authutils.OAUTHResponse{
 						Scope:        "",
 						ExpiresIn:    3600,
@@ -780,7 +785,7 @@ func TestHealthCRMLib_GetServices(t *testing.T) {
 			httpmock.Activate()
 			defer httpmock.DeactivateAndReset()
 			MockAuthenticate()
-			h, err := NewHealthCRMLib()
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
 			if err != nil {
 				t.Errorf("unable to initialize sdk: %v", err)
 			}
@@ -825,7 +830,7 @@ func TestHealthCRMLib_GetPractitionerByID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.name == "Happy case: get practitioner by practitionerID" {
-				path := fmt.Sprintf("%s/v1/practitioners/practitioners/%s/", BaseURL, tt.args.practitionerID)
+				path := fmt.Sprintf("%s/v1/practitioners/practitioners/%s/", baseURL, tt.args.practitionerID)
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
 					resp := &Practitioner{
 						ID: gofakeit.UUID(),
@@ -835,7 +840,7 @@ func TestHealthCRMLib_GetPractitionerByID(t *testing.T) {
 			}
 
 			if tt.name == "Sad case: error getting practitioner by practitionerID" {
-				path := fmt.Sprintf("%s/v1/practitioners/practitioners/%s/", BaseURL, gofakeit.UUID())
+				path := fmt.Sprintf("%s/v1/practitioners/practitioners/%s/", baseURL, gofakeit.UUID())
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
 					return httpmock.NewJsonResponse(http.StatusBadRequest, nil)
 				})
@@ -844,7 +849,7 @@ func TestHealthCRMLib_GetPractitionerByID(t *testing.T) {
 			httpmock.Activate()
 			defer httpmock.DeactivateAndReset()
 			MockAuthenticate()
-			h, err := NewHealthCRMLib()
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
 			if err != nil {
 				t.Errorf("unable to initialize sdk: %v", err)
 			}
@@ -875,8 +880,8 @@ func TestHealthCRMLib_GetPractitioners(t *testing.T) {
 				ctx: context.Background(),
 				filters: FilterPractitionersInput{
 					Pagination: &Pagination{
-						Page:     "2",
-						PageSize: "5",
+						Page:     2,
+						PageSize: 5,
 					},
 					CrmServiceCode: "05",
 				},
@@ -901,7 +906,7 @@ func TestHealthCRMLib_GetPractitioners(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.name == "Happy case: get all practitioners" {
-				path := fmt.Sprintf("%s/v1/practitioners/practitioners/", BaseURL)
+				path := fmt.Sprintf("%s/v1/practitioners/practitioners/", baseURL)
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
 					resp := &Practitioners{
 						Results: []Practitioner{
@@ -919,14 +924,14 @@ func TestHealthCRMLib_GetPractitioners(t *testing.T) {
 			}
 
 			if tt.name == "Sad case: unable to get all practitioners" {
-				path := fmt.Sprintf("%s/v1/practitioners/practitioners/", BaseURL)
+				path := fmt.Sprintf("%s/v1/practitioners/practitioners/", baseURL)
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
 					return httpmock.NewJsonResponse(http.StatusBadGateway, nil)
 				})
 			}
 
 			if tt.name == "Sad case: wrong http method" {
-				path := fmt.Sprintf("%s/v1/practitioners/practitioners/", BaseURL)
+				path := fmt.Sprintf("%s/v1/practitioners/practitioners/", baseURL)
 				httpmock.RegisterResponder(http.MethodPost, path, func(r *http.Request) (*http.Response, error) {
 					return httpmock.NewJsonResponse(http.StatusBadGateway, nil)
 				})
@@ -935,7 +940,7 @@ func TestHealthCRMLib_GetPractitioners(t *testing.T) {
 			httpmock.Activate()
 			defer httpmock.DeactivateAndReset()
 			MockAuthenticate()
-			h, err := NewHealthCRMLib()
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
 			if err != nil {
 				t.Errorf("unable to initialize sdk: %v", err)
 			}
@@ -966,8 +971,8 @@ func TestHealthCRMLib_GetSpecialties(t *testing.T) {
 			args: args{
 				ctx: context.Background(),
 				pagination: &Pagination{
-					Page:     "2",
-					PageSize: "5",
+					Page:     2,
+					PageSize: 5,
 				},
 				crmServiceCode: "05",
 			},
@@ -1000,7 +1005,7 @@ func TestHealthCRMLib_GetSpecialties(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.name == "Happy case: get all specialties" {
-				path := fmt.Sprintf("%s/v1/practitioners/specialties/", BaseURL)
+				path := fmt.Sprintf("%s/v1/practitioners/specialties/", baseURL)
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
 					resp := &Specialties{
 						Results: []PractitionerSpecialty{
@@ -1016,19 +1021,19 @@ func TestHealthCRMLib_GetSpecialties(t *testing.T) {
 			}
 
 			if tt.name == "Sad case: unable to get all specialties" {
-				path := fmt.Sprintf("%s/v1/practitioners/specialties/", BaseURL)
+				path := fmt.Sprintf("%s/v1/practitioners/specialties/", baseURL)
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
 					return httpmock.NewJsonResponse(http.StatusBadGateway, nil)
 				})
 			}
 			if tt.name == "Sad case: unable to get specialties" {
-				path := fmt.Sprintf("%s/v1/practitioners/specialties/?specialty=1b5baf1a-1aec-48bd-951c-01896e5fe5a8", BaseURL)
+				path := fmt.Sprintf("%s/v1/practitioners/specialties/?specialty=1b5baf1a-1aec-48bd-951c-01896e5fe5a8", baseURL)
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
 					return httpmock.NewJsonResponse(http.StatusBadGateway, nil)
 				})
 			}
 			if tt.name == "Sad case: wrong http method" {
-				path := fmt.Sprintf("%s/v1/practitioners/specialties/", BaseURL)
+				path := fmt.Sprintf("%s/v1/practitioners/specialties/", baseURL)
 				httpmock.RegisterResponder(http.MethodPost, path, func(r *http.Request) (*http.Response, error) {
 					return httpmock.NewJsonResponse(http.StatusBadGateway, nil)
 				})
@@ -1037,7 +1042,7 @@ func TestHealthCRMLib_GetSpecialties(t *testing.T) {
 			httpmock.Activate()
 			defer httpmock.DeactivateAndReset()
 			MockAuthenticate()
-			h, err := NewHealthCRMLib()
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
 			if err != nil {
 				t.Errorf("unable to initialize sdk: %v", err)
 			}
@@ -1068,8 +1073,8 @@ func TestHealthCRMLib_GetFacilitiesOfferingAService(t *testing.T) {
 				ctx:       context.Background(),
 				serviceID: "227305a7-b9a5-4ca7-a211-71210d68206c",
 				pagination: &Pagination{
-					Page:     "1",
-					PageSize: "20",
+					Page:     1,
+					PageSize: 20,
 				},
 			},
 			wantErr: false,
@@ -1079,8 +1084,8 @@ func TestHealthCRMLib_GetFacilitiesOfferingAService(t *testing.T) {
 			args: args{
 				ctx: context.Background(),
 				pagination: &Pagination{
-					Page:     "2",
-					PageSize: "20",
+					Page:     2,
+					PageSize: 20,
 				},
 			},
 			wantErr: true,
@@ -1091,8 +1096,8 @@ func TestHealthCRMLib_GetFacilitiesOfferingAService(t *testing.T) {
 				ctx:       context.Background(),
 				serviceID: gofakeit.UUID(),
 				pagination: &Pagination{
-					Page:     "2",
-					PageSize: "20",
+					Page:     2,
+					PageSize: 20,
 				},
 			},
 			wantErr: true,
@@ -1101,7 +1106,7 @@ func TestHealthCRMLib_GetFacilitiesOfferingAService(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.name == "Happy case: get facilities offering a service" {
-				path := fmt.Sprintf("%s/v1/facilities/facilities/", BaseURL)
+				path := fmt.Sprintf("%s/v1/facilities/facilities/", baseURL)
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
 					resp := &FacilityOutput{
 						ID:           gofakeit.UUID(),
@@ -1131,13 +1136,13 @@ func TestHealthCRMLib_GetFacilitiesOfferingAService(t *testing.T) {
 				})
 			}
 			if tt.name == "Sad case: unable to get facilities offering a service" {
-				path := fmt.Sprintf("%s/v1/facilities/facilities/", BaseURL)
+				path := fmt.Sprintf("%s/v1/facilities/facilities/", baseURL)
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
 					return httpmock.NewJsonResponse(http.StatusBadGateway, nil)
 				})
 			}
 			if tt.name == "Sad case: unable to make request" {
-				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", serverutils.MustGetEnvVar("HEALTH_CRM_AUTH_SERVER_ENDPOINT")), func(r *http.Request) (*http.Response, error) {
+				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", testAuthServerEndpoint), func(r *http.Request) (*http.Response, error) {
 					resp := authutils.OAUTHResponse{
 						Scope:        "",
 						ExpiresIn:    3600,
@@ -1152,7 +1157,7 @@ func TestHealthCRMLib_GetFacilitiesOfferingAService(t *testing.T) {
 			httpmock.Activate()
 			defer httpmock.DeactivateAndReset()
 			MockAuthenticate()
-			h, err := NewHealthCRMLib()
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
 			if err != nil {
 				t.Errorf("unable to initialize sdk: %v", err)
 			}
@@ -1231,7 +1236,7 @@ func TestHealthCRMLib_CreateService(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.name == "Happy case: create a service" {
-				path := fmt.Sprintf("%s/v1/facilities/services/", BaseURL)
+				path := fmt.Sprintf("%s/v1/facilities/services/", baseURL)
 				httpmock.RegisterResponder(http.MethodPost, path, func(r *http.Request) (*http.Response, error) {
 					resp := &FacilityService{
 						ID:          gofakeit.UUID(),
@@ -1250,13 +1255,13 @@ func TestHealthCRMLib_CreateService(t *testing.T) {
 				})
 			}
 			if tt.name == "Sad case: unable to create a service" {
-				path := fmt.Sprintf("%s/v1/facilities/services/", BaseURL)
+				path := fmt.Sprintf("%s/v1/facilities/services/", baseURL)
 				httpmock.RegisterResponder(http.MethodPost, path, func(r *http.Request) (*http.Response, error) {
 					return httpmock.NewJsonResponse(http.StatusBadGateway, nil)
 				})
 			}
 			if tt.name == "Sad case: unable to make request" {
-				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", serverutils.MustGetEnvVar("HEALTH_CRM_AUTH_SERVER_ENDPOINT")), func(r *http.Request) (*http.Response, error) {
+				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", testAuthServerEndpoint), func(r *http.Request) (*http.Response, error) {
 					resp := authutils.OAUTHResponse{
 						Scope:        "",
 						ExpiresIn:    3600,
@@ -1271,7 +1276,7 @@ func TestHealthCRMLib_CreateService(t *testing.T) {
 			httpmock.Activate()
 			defer httpmock.DeactivateAndReset()
 			MockAuthenticate()
-			h, err := NewHealthCRMLib()
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
 			if err != nil {
 				t.Errorf("unable to initialize sdk: %v", err)
 			}
@@ -1359,7 +1364,7 @@ func TestHealthCRMLib_LinkServiceToFacility(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.name == "Happy case: link facility to service" {
-				path := fmt.Sprintf("%s/v1/facilities/facilities/b6792568-564f-41ca-b951-69fae05e6ca1/add_services/", BaseURL)
+				path := fmt.Sprintf("%s/v1/facilities/facilities/b6792568-564f-41ca-b951-69fae05e6ca1/add_services/", baseURL)
 				httpmock.RegisterResponder(http.MethodPost, path, func(r *http.Request) (*http.Response, error) {
 					resp := &FacilityService{
 						ID:          gofakeit.UUID(),
@@ -1378,13 +1383,13 @@ func TestHealthCRMLib_LinkServiceToFacility(t *testing.T) {
 				})
 			}
 			if tt.name == "Sad case: unable to link facility to service" {
-				path := fmt.Sprintf("%s/v1/facilities/facilities/b6792568-564f-41ca-b951-69fae05e6ca1/add_services/", BaseURL)
+				path := fmt.Sprintf("%s/v1/facilities/facilities/b6792568-564f-41ca-b951-69fae05e6ca1/add_services/", baseURL)
 				httpmock.RegisterResponder(http.MethodPost, path, func(r *http.Request) (*http.Response, error) {
 					return httpmock.NewJsonResponse(http.StatusBadGateway, nil)
 				})
 			}
 			if tt.name == "Sad case: unable to make request" {
-				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", serverutils.MustGetEnvVar("HEALTH_CRM_AUTH_SERVER_ENDPOINT")), func(r *http.Request) (*http.Response, error) {
+				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", testAuthServerEndpoint), func(r *http.Request) (*http.Response, error) {
 					resp := authutils.OAUTHResponse{
 						Scope:        "",
 						ExpiresIn:    3600,
@@ -1399,7 +1404,7 @@ func TestHealthCRMLib_LinkServiceToFacility(t *testing.T) {
 			httpmock.Activate()
 			defer httpmock.DeactivateAndReset()
 			MockAuthenticate()
-			h, err := NewHealthCRMLib()
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
 			if err != nil {
 				t.Errorf("unable to initialize sdk: %v", err)
 			}
@@ -1451,7 +1456,7 @@ func TestHealthCRMLib_GetService(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.name == "Happy case: get service" {
-				path := fmt.Sprintf("%s/v1/facilities/services/b7142d0f-88a0-436b-976d-4ecc86482107", BaseURL)
+				path := fmt.Sprintf("%s/v1/facilities/services/b7142d0f-88a0-436b-976d-4ecc86482107", baseURL)
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
 					resp := &FacilityService{
 						ID:          gofakeit.UUID(),
@@ -1470,13 +1475,13 @@ func TestHealthCRMLib_GetService(t *testing.T) {
 				})
 			}
 			if tt.name == "Sad case: unable to get a service" {
-				path := fmt.Sprintf("%s/v1/facilities/services/b7142d0f-88a0-436b-976d-4ecc86482107", BaseURL)
+				path := fmt.Sprintf("%s/v1/facilities/services/b7142d0f-88a0-436b-976d-4ecc86482107", baseURL)
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
 					return httpmock.NewJsonResponse(http.StatusBadGateway, nil)
 				})
 			}
 			if tt.name == "Sad case: unable to make request" {
-				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", serverutils.MustGetEnvVar("HEALTH_CRM_AUTH_SERVER_ENDPOINT")), func(r *http.Request) (*http.Response, error) {
+				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", testAuthServerEndpoint), func(r *http.Request) (*http.Response, error) {
 					resp := authutils.OAUTHResponse{
 						Scope:        "",
 						ExpiresIn:    3600,
@@ -1491,7 +1496,7 @@ func TestHealthCRMLib_GetService(t *testing.T) {
 			httpmock.Activate()
 			defer httpmock.DeactivateAndReset()
 			MockAuthenticate()
-			h, err := NewHealthCRMLib()
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
 			if err != nil {
 				t.Errorf("unable to initialize sdk: %v", err)
 			}
@@ -1581,7 +1586,7 @@ func TestHealthCRMLib_CreateProfile(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.name == "Happy Case: Create Profile" {
-				path := fmt.Sprintf("%s/v1/identities/profiles/", BaseURL)
+				path := fmt.Sprintf("%s/v1/identities/profiles/", baseURL)
 				httpmock.RegisterResponder(http.MethodPost, path, func(r *http.Request) (*http.Response, error) {
 					resp := &ProfileOutput{
 						ID:        gofakeit.UUID(),
@@ -1593,7 +1598,7 @@ func TestHealthCRMLib_CreateProfile(t *testing.T) {
 				})
 			}
 			if tt.name == "Sad Case: Unable To Create Profile" {
-				path := fmt.Sprintf("%s/v1/identities/profiles/", BaseURL)
+				path := fmt.Sprintf("%s/v1/identities/profiles/", baseURL)
 				httpmock.RegisterResponder(http.MethodPost, path, func(r *http.Request) (*http.Response, error) {
 					resp := &ProfileInput{
 						ProfileID:     gofakeit.UUID(),
@@ -1612,7 +1617,7 @@ func TestHealthCRMLib_CreateProfile(t *testing.T) {
 				})
 			}
 			if tt.name == "Sad Case: Unable To Make Request" {
-				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", serverutils.MustGetEnvVar("HEALTH_CRM_AUTH_SERVER_ENDPOINT")), func(r *http.Request) (*http.Response, error) {
+				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", testAuthServerEndpoint), func(r *http.Request) (*http.Response, error) {
 					resp := authutils.OAUTHResponse{
 						Scope:        "",
 						ExpiresIn:    3600,
@@ -1627,7 +1632,7 @@ func TestHealthCRMLib_CreateProfile(t *testing.T) {
 			httpmock.Activate()
 			defer httpmock.DeactivateAndReset()
 			MockAuthenticate()
-			h, err := NewHealthCRMLib()
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
 			if err != nil {
 				t.Errorf("unable to initialize sdk: %v", err)
 			}
@@ -1706,7 +1711,7 @@ func TestHealthCRMLib_GetMultipleServices(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			path := fmt.Sprintf("%s/v1/facilities/services?service_ids=0fee2792-dffc-40d3-a744-2a70732b1053,56c62083-c7b4-4055-8d44-6cc7446ac1d0,8474ea55-8ede-4bc6-aa67-f53ed5456a03", BaseURL)
+			path := fmt.Sprintf("%s/v1/facilities/services?service_ids=0fee2792-dffc-40d3-a744-2a70732b1053,56c62083-c7b4-4055-8d44-6cc7446ac1d0,8474ea55-8ede-4bc6-aa67-f53ed5456a03", baseURL)
 
 			if tt.name == "Happy case: get list of services" {
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
@@ -1751,7 +1756,7 @@ func TestHealthCRMLib_GetMultipleServices(t *testing.T) {
 			}
 
 			if tt.name == "Sad case: unable to make request" {
-				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", serverutils.MustGetEnvVar("HEALTH_CRM_AUTH_SERVER_ENDPOINT")), func(r *http.Request) (*http.Response, error) {
+				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", testAuthServerEndpoint), func(r *http.Request) (*http.Response, error) {
 					resp := authutils.OAUTHResponse{
 						Scope:        "",
 						ExpiresIn:    3600,
@@ -1768,7 +1773,7 @@ func TestHealthCRMLib_GetMultipleServices(t *testing.T) {
 
 			MockAuthenticate()
 
-			h, err := NewHealthCRMLib()
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
 			if err != nil {
 				t.Errorf("unable to initialize sdk: %v", err)
 			}
@@ -1848,7 +1853,7 @@ func TestHealthCRMLib_GetMultipleFacilities(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			path := fmt.Sprintf("%s/v1/facilities/facilities?facility_ids=556a1dd9-fbb5-40c2-a623-dde9a2335597,7f59c528-8d9e-4a97-a9e5-bea7d7938c0e,b8246d32-b9e7-422c-b3bb-a1066dec8561", BaseURL)
+			path := fmt.Sprintf("%s/v1/facilities/facilities?facility_ids=556a1dd9-fbb5-40c2-a623-dde9a2335597,7f59c528-8d9e-4a97-a9e5-bea7d7938c0e,b8246d32-b9e7-422c-b3bb-a1066dec8561", baseURL)
 
 			if tt.name == "Happy case: get list of facilities" {
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
@@ -1877,7 +1882,7 @@ func TestHealthCRMLib_GetMultipleFacilities(t *testing.T) {
 			}
 
 			if tt.name == "Sad case: unable to make request" {
-				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", serverutils.MustGetEnvVar("HEALTH_CRM_AUTH_SERVER_ENDPOINT")), func(r *http.Request) (*http.Response, error) {
+				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", testAuthServerEndpoint), func(r *http.Request) (*http.Response, error) {
 					resp := authutils.OAUTHResponse{
 						Scope:        "",
 						ExpiresIn:    3600,
@@ -1894,7 +1899,7 @@ func TestHealthCRMLib_GetMultipleFacilities(t *testing.T) {
 
 			MockAuthenticate()
 
-			h, err := NewHealthCRMLib()
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
 			if err != nil {
 				t.Errorf("unable to initialize sdk: %v", err)
 			}
@@ -2003,7 +2008,7 @@ func TestHealthCRMLib_GetPersonIdentifiers(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			path := fmt.Sprintf("%s/v1/identities/persons/0000010000000041/identifiers/", BaseURL)
+			path := fmt.Sprintf("%s/v1/identities/persons/0000010000000041/identifiers/", baseURL)
 
 			if tt.name == "Happy case: get list of identifiers" {
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
@@ -2123,7 +2128,7 @@ func TestHealthCRMLib_GetPersonIdentifiers(t *testing.T) {
 			}
 
 			if tt.name == "Sad case: unable to make request" {
-				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", serverutils.MustGetEnvVar("HEALTH_CRM_AUTH_SERVER_ENDPOINT")), func(r *http.Request) (*http.Response, error) {
+				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", testAuthServerEndpoint), func(r *http.Request) (*http.Response, error) {
 					resp := authutils.OAUTHResponse{
 						Scope:        "",
 						ExpiresIn:    3600,
@@ -2140,7 +2145,7 @@ func TestHealthCRMLib_GetPersonIdentifiers(t *testing.T) {
 
 			MockAuthenticate()
 
-			h, err := NewHealthCRMLib()
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
 			if err != nil {
 				t.Errorf("unable to initialize sdk: %v", err)
 			}
@@ -2207,7 +2212,7 @@ func TestHealthCRMLib_GetPersonContacts(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			path := fmt.Sprintf("%s/v1/identities/persons/0000010000000041/contacts/", BaseURL)
+			path := fmt.Sprintf("%s/v1/identities/persons/0000010000000041/contacts/", baseURL)
 
 			if tt.name == "Happy case: get list of contacts" {
 				httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
@@ -2272,7 +2277,7 @@ func TestHealthCRMLib_GetPersonContacts(t *testing.T) {
 			}
 
 			if tt.name == "Sad case: unable to make request" {
-				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", serverutils.MustGetEnvVar("HEALTH_CRM_AUTH_SERVER_ENDPOINT")), func(r *http.Request) (*http.Response, error) {
+				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", testAuthServerEndpoint), func(r *http.Request) (*http.Response, error) {
 					resp := authutils.OAUTHResponse{
 						Scope:        "",
 						ExpiresIn:    3600,
@@ -2289,7 +2294,7 @@ func TestHealthCRMLib_GetPersonContacts(t *testing.T) {
 
 			MockAuthenticate()
 
-			h, err := NewHealthCRMLib()
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
 			if err != nil {
 				t.Errorf("unable to initialize sdk: %v", err)
 			}
@@ -2380,7 +2385,7 @@ func TestHealthCRMLib_MatchProfile(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.name == "Happy Case: Match Profile" {
-				path := fmt.Sprintf("%s/v1/identities/profiles/match_profile/", BaseURL)
+				path := fmt.Sprintf("%s/v1/identities/profiles/match_profile/", baseURL)
 				httpmock.RegisterResponder(http.MethodPost, path, func(r *http.Request) (*http.Response, error) {
 					resp := &ProfileOutput{
 						ID:             gofakeit.UUID(),
@@ -2393,7 +2398,7 @@ func TestHealthCRMLib_MatchProfile(t *testing.T) {
 				})
 			}
 			if tt.name == "Sad Case: Unable To Match Profile" {
-				path := fmt.Sprintf("%s/v1/identities/profiles/match_profile/", BaseURL)
+				path := fmt.Sprintf("%s/v1/identities/profiles/match_profile/", baseURL)
 				httpmock.RegisterResponder(http.MethodPost, path, func(r *http.Request) (*http.Response, error) {
 					resp := &ProfileInput{
 						ProfileID:     gofakeit.UUID(),
@@ -2412,7 +2417,7 @@ func TestHealthCRMLib_MatchProfile(t *testing.T) {
 				})
 			}
 			if tt.name == "Sad Case: Unable To Make Request" {
-				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", serverutils.MustGetEnvVar("HEALTH_CRM_AUTH_SERVER_ENDPOINT")), func(r *http.Request) (*http.Response, error) {
+				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", testAuthServerEndpoint), func(r *http.Request) (*http.Response, error) {
 					resp := authutils.OAUTHResponse{
 						Scope:        "",
 						ExpiresIn:    3600,
@@ -2427,7 +2432,7 @@ func TestHealthCRMLib_MatchProfile(t *testing.T) {
 			httpmock.Activate()
 			defer httpmock.DeactivateAndReset()
 			MockAuthenticate()
-			h, err := NewHealthCRMLib()
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
 			if err != nil {
 				t.Errorf("unable to initialize sdk: %v", err)
 			}
@@ -2523,7 +2528,7 @@ func TestHealthCRMLib_VerifyIdentifierDocument(t *testing.T) {
 				})
 			}
 
-			h, err := NewHealthCRMLib()
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
 			if err != nil {
 				t.Errorf("unable to initialize sdk: %v", err)
 			}
@@ -2536,3 +2541,512 @@ func TestHealthCRMLib_VerifyIdentifierDocument(t *testing.T) {
 		})
 	}
 }
+
+func TestHealthCRMLib_Close(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	MockAuthenticate()
+
+	h, err := NewHealthCRMLib(testOptions(baseURL)...)
+	if err != nil {
+		t.Fatalf("unable to initialize sdk: %v", err)
+	}
+
+	err = h.Close()
+	if err != nil {
+		t.Fatalf("HealthCRMLib.Close() error = %v", err)
+	}
+
+	select {
+	case <-h.client.backgroundDone:
+	default:
+		t.Errorf("HealthCRMLib.Close() did not stop the background token refresher")
+	}
+
+	err = h.Close()
+	if !errors.Is(err, ErrClientClosed) {
+		t.Errorf("HealthCRMLib.Close() called twice error = %v, want %v", err, ErrClientClosed)
+	}
+
+	_, err = h.GetFacilityByID(context.Background(), gofakeit.UUID())
+	if !errors.Is(err, ErrClientClosed) {
+		t.Errorf("HealthCRMLib.GetFacilityByID() after close error = %v, want %v", err, ErrClientClosed)
+	}
+}
+
+func TestHealthCRMLib_Shutdown(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	MockAuthenticate()
+
+	h, err := NewHealthCRMLib(testOptions(baseURL)...)
+	if err != nil {
+		t.Fatalf("unable to initialize sdk: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err = h.Shutdown(ctx)
+	if err != nil {
+		t.Fatalf("HealthCRMLib.Shutdown() error = %v", err)
+	}
+
+	err = h.Shutdown(ctx)
+	if !errors.Is(err, ErrClientClosed) {
+		t.Errorf("HealthCRMLib.Shutdown() called twice error = %v, want %v", err, ErrClientClosed)
+	}
+}
+
+func TestHealthCRMLib_TokenExpiry(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	MockAuthenticate()
+
+	h, err := NewHealthCRMLib(testOptions(baseURL)...)
+	if err != nil {
+		t.Fatalf("unable to initialize sdk: %v", err)
+	}
+	defer h.Close()
+
+	expiry := h.TokenExpiry()
+	if time.Until(expiry) <= 59*time.Minute || time.Until(expiry) > time.Hour {
+		t.Errorf("HealthCRMLib.TokenExpiry() = %v, want about an hour from now", expiry)
+	}
+}
+
+func TestHealthCRMLib_APIErrors(t *testing.T) {
+	ctx := context.Background()
+
+	methods := []struct {
+		name   string
+		method string
+		path   string
+		call   func(h *HealthCRMLib) error
+	}{
+		{
+			name:   "CreateFacility",
+			method: http.MethodPost,
+			path:   "/v1/facilities/facilities/",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.CreateFacility(ctx, &Facility{Name: "Test Facility"})
+				return err
+			},
+		},
+		{
+			name:   "GetFacilityByID",
+			method: http.MethodGet,
+			path:   "/v1/facilities/facilities/123/",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.GetFacilityByID(ctx, "123")
+				return err
+			},
+		},
+		{
+			name:   "UpdateFacility",
+			method: http.MethodPatch,
+			path:   "/v1/facilities/facilities/123/",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.UpdateFacility(ctx, "123", &Facility{Name: "Test Facility"})
+				return err
+			},
+		},
+		{
+			name:   "GetServices",
+			method: http.MethodGet,
+			path:   "/v1/facilities/services/",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.GetServices(ctx, nil, "05")
+				return err
+			},
+		},
+		{
+			name:   "GetPractitioners",
+			method: http.MethodGet,
+			path:   "/v1/practitioners/practitioners/",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.GetPractitioners(ctx, FilterPractitionersInput{CrmServiceCode: "05"})
+				return err
+			},
+		},
+		{
+			name:   "GetPractitionerByID",
+			method: http.MethodGet,
+			path:   "/v1/practitioners/practitioners/123/",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.GetPractitionerByID(ctx, "123")
+				return err
+			},
+		},
+		{
+			name:   "GetSpecialties",
+			method: http.MethodGet,
+			path:   "/v1/practitioners/specialties/",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.GetSpecialties(ctx, nil, "05")
+				return err
+			},
+		},
+		{
+			name:   "GetFacilitiesOfferingAService",
+			method: http.MethodGet,
+			path:   "/v1/facilities/facilities/",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.GetFacilitiesOfferingAService(ctx, "123", nil)
+				return err
+			},
+		},
+		{
+			name:   "CreateService",
+			method: http.MethodPost,
+			path:   "/v1/facilities/services/",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.CreateService(ctx, FacilityServiceInput{Name: "Chemotherapy"})
+				return err
+			},
+		},
+		{
+			name:   "LinkServiceToFacility",
+			method: http.MethodPost,
+			path:   "/v1/facilities/facilities/123/add_services/",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.LinkServiceToFacility(ctx, "123", []*FacilityServiceInput{{Name: "Chemotherapy"}})
+				return err
+			},
+		},
+		{
+			name:   "GetFacilities",
+			method: http.MethodGet,
+			path:   "/v1/facilities/facilities/",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.GetFacilities(ctx, FilterFacilitiesInput{CrmServiceCode: "05"})
+				return err
+			},
+		},
+		{
+			name:   "GetService",
+			method: http.MethodGet,
+			path:   "/v1/facilities/services/123",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.GetService(ctx, "123")
+				return err
+			},
+		},
+		{
+			name:   "CreateProfile",
+			method: http.MethodPost,
+			path:   "/v1/identities/profiles/",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.CreateProfile(ctx, &ProfileInput{FirstName: "Jane"})
+				return err
+			},
+		},
+		{
+			name:   "MatchProfile",
+			method: http.MethodPost,
+			path:   "/v1/identities/profiles/match_profile/",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.MatchProfile(ctx, &ProfileInput{FirstName: "Jane"})
+				return err
+			},
+		},
+		{
+			name:   "GetMultipleServices",
+			method: http.MethodGet,
+			path:   "/v1/facilities/services",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.GetMultipleServices(ctx, []string{"123", "456"})
+				return err
+			},
+		},
+		{
+			name:   "GetMultipleFacilities",
+			method: http.MethodGet,
+			path:   "/v1/facilities/facilities",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.GetMultipleFacilities(ctx, []string{"123", "456"})
+				return err
+			},
+		},
+		{
+			name:   "GetPersonIdentifiers",
+			method: http.MethodGet,
+			path:   "/v1/identities/persons/123/identifiers/",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.GetPersonIdentifiers(ctx, "123", nil)
+				return err
+			},
+		},
+		{
+			name:   "GetPersonContacts",
+			method: http.MethodGet,
+			path:   "/v1/identities/persons/123/contacts/",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.GetPersonContacts(ctx, "123")
+				return err
+			},
+		},
+		{
+			name:   "VerifyIdentifierDocument",
+			method: http.MethodPost,
+			path:   "/v1/identities/identifiers/verify/",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.VerifyIdentifierDocument(ctx, IDVerificationInput{IDUrl: "https://example.com/id.png"})
+				return err
+			},
+		},
+	}
+
+	statuses := []struct {
+		statusCode int
+		body       string
+		sentinel   error
+	}{
+		{statusCode: http.StatusBadRequest, body: `{"name": ["This field is required."]}`, sentinel: ErrValidation},
+		{statusCode: http.StatusUnauthorized, body: `{"detail": "Invalid token."}`, sentinel: ErrUnauthorized},
+		{statusCode: http.StatusNotFound, body: `{"detail": "Not found."}`, sentinel: ErrNotFound},
+		{statusCode: http.StatusConflict, body: `{"detail": "Already exists."}`, sentinel: ErrConflict},
+		{statusCode: http.StatusInternalServerError, body: `<html>Server Error</html>`, sentinel: ErrServerError},
+	}
+
+	for _, m := range methods {
+		for _, status := range statuses {
+			t.Run(fmt.Sprintf("%s returns %d", m.name, status.statusCode), func(t *testing.T) {
+				httpmock.Activate()
+				defer httpmock.DeactivateAndReset()
+				MockAuthenticate()
+
+				httpmock.RegisterResponder(m.method, fmt.Sprintf("%s%s", baseURL, m.path), httpmock.NewStringResponder(status.statusCode, status.body))
+
+				h, err := NewHealthCRMLib(testOptions(baseURL, WithRetryPolicy(NoRetryPolicy()))...)
+				if err != nil {
+					t.Fatalf("unable to initialize sdk: %v", err)
+				}
+				defer h.Close()
+
+				err = m.call(h)
+
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) {
+					t.Fatalf("HealthCRMLib.%s() error = %v, want an *APIError", m.name, err)
+				}
+
+				if apiErr.StatusCode != status.statusCode || apiErr.Method != m.method || apiErr.Path != m.path {
+					t.Errorf("HealthCRMLib.%s() error = %d %s %s, want %d %s %s", m.name, apiErr.StatusCode, apiErr.Method, apiErr.Path, status.statusCode, m.method, m.path)
+				}
+
+				if string(apiErr.Body) != status.body {
+					t.Errorf("HealthCRMLib.%s() error body = %q, want %q", m.name, apiErr.Body, status.body)
+				}
+
+				if !errors.Is(err, status.sentinel) {
+					t.Errorf("errors.Is(%v, %v) = false, want true", err, status.sentinel)
+				}
+			})
+		}
+	}
+}
+
+func TestHealthCRMLib_ValidationErrors(t *testing.T) {
+	ctx := context.Background()
+	body := `{"name": ["This field is required."], "contacts": [{"contact_value": ["Enter a valid phone number."]}]}`
+
+	tests := []struct {
+		name string
+		path string
+		call func(h *HealthCRMLib) error
+	}{
+		{
+			name: "CreateFacility",
+			path: "/v1/facilities/facilities/",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.CreateFacility(ctx, &Facility{Contacts: []Contacts{{ContactType: "PHONE_NUMBER", ContactValue: "07"}}})
+				return err
+			},
+		},
+		{
+			name: "CreateProfile",
+			path: "/v1/identities/profiles/",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.CreateProfile(ctx, &ProfileInput{})
+				return err
+			},
+		},
+		{
+			name: "CreateService",
+			path: "/v1/facilities/services/",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.CreateService(ctx, FacilityServiceInput{})
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			MockAuthenticate()
+
+			httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s%s", baseURL, tt.path), httpmock.NewStringResponder(http.StatusBadRequest, body))
+
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
+			if err != nil {
+				t.Fatalf("unable to initialize sdk: %v", err)
+			}
+			defer h.Close()
+
+			err = tt.call(h)
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("HealthCRMLib.%s() error = %v, want a *ValidationError", tt.name, err)
+			}
+
+			if got := validationErr.Messages("contacts[0].contact_value"); len(got) != 1 || got[0] != "Enter a valid phone number." {
+				t.Errorf("HealthCRMLib.%s() contacts[0].contact_value messages = %v", tt.name, got)
+			}
+
+			if got := validationErr.Messages("name"); len(got) != 1 || got[0] != "This field is required." {
+				t.Errorf("HealthCRMLib.%s() name messages = %v", tt.name, got)
+			}
+
+			if validationErr.Err.Path != tt.path {
+				t.Errorf("HealthCRMLib.%s() error path = %s, want %s", tt.name, validationErr.Err.Path, tt.path)
+			}
+		})
+	}
+}
+
+func TestHealthCRMLib_DeleteFacility(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		wantErr    error
+	}{
+		{
+			name:       "Happy case: delete facility",
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:       "Sad case: facility not found",
+			statusCode: http.StatusNotFound,
+			wantErr:    ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			MockAuthenticate()
+
+			path := fmt.Sprintf("%s/v1/facilities/facilities/%s/", baseURL, "123")
+			httpmock.RegisterResponder(http.MethodDelete, path, httpmock.NewStringResponder(tt.statusCode, ""))
+
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
+			if err != nil {
+				t.Fatalf("unable to initialize sdk: %v", err)
+			}
+			defer h.Close()
+
+			err = h.DeleteFacility(context.Background(), "123")
+			if (err != nil) != (tt.wantErr != nil) || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+				t.Errorf("HealthCRMLib.DeleteFacility() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if count := httpmock.GetCallCountInfo()["DELETE "+path]; count != 1 {
+				t.Errorf("HealthCRMLib.DeleteFacility() sent %d requests, want 1", count)
+			}
+		})
+	}
+}
+
+func TestHealthCRMLib_FacilityStatusTransitions(t *testing.T) {
+	tests := []struct {
+		name       string
+		change     func(h *HealthCRMLib) (*FacilityOutput, error)
+		statusCode int
+		wantStatus FacilityStatus
+		wantErr    bool
+	}{
+		{
+			name: "Happy case: deactivate facility",
+			change: func(h *HealthCRMLib) (*FacilityOutput, error) {
+				return h.DeactivateFacility(context.Background(), "123")
+			},
+			statusCode: http.StatusOK,
+			wantStatus: FacilityStatusInactive,
+		},
+		{
+			name: "Happy case: reactivate facility",
+			change: func(h *HealthCRMLib) (*FacilityOutput, error) {
+				return h.ReactivateFacility(context.Background(), "123")
+			},
+			statusCode: http.StatusOK,
+			wantStatus: FacilityStatusPublished,
+		},
+		{
+			name: "Happy case: move facility back to draft",
+			change: func(h *HealthCRMLib) (*FacilityOutput, error) {
+				return h.UpdateFacilityStatus(context.Background(), "123", FacilityStatusDraft)
+			},
+			statusCode: http.StatusOK,
+			wantStatus: FacilityStatusDraft,
+		},
+		{
+			name: "Sad case: invalid status",
+			change: func(h *HealthCRMLib) (*FacilityOutput, error) {
+				return h.UpdateFacilityStatus(context.Background(), "123", FacilityStatus("CLOSED"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "Sad case: transition rejected",
+			change: func(h *HealthCRMLib) (*FacilityOutput, error) {
+				return h.DeactivateFacility(context.Background(), "123")
+			},
+			statusCode: http.StatusBadRequest,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			MockAuthenticate()
+
+			var sent facilityStatusInput
+
+			path := fmt.Sprintf("%s/v1/facilities/facilities/%s/", baseURL, "123")
+			httpmock.RegisterResponder(http.MethodPatch, path, func(r *http.Request) (*http.Response, error) {
+				if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+					return nil, err
+				}
+
+				if tt.statusCode != http.StatusOK {
+					return httpmock.NewStringResponse(tt.statusCode, `{"status": ["Invalid status transition."]}`), nil
+				}
+
+				return httpmock.NewJsonResponse(http.StatusOK, &FacilityOutput{ID: "123", Status: sent.Status})
+			})
+
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
+			if err != nil {
+				t.Fatalf("unable to initialize sdk: %v", err)
+			}
+			defer h.Close()
+
+			facility, err := tt.change(h)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("changing the facility status error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if sent.Status != tt.wantStatus || facility.Status != tt.wantStatus {
+				t.Errorf("changing the facility status sent %s and got %s, want %s", sent.Status, facility.Status, tt.wantStatus)
+			}
+		})
+	}
+}
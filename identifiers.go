@@ -0,0 +1,248 @@
+package healthcrm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/savannahghi/scalarutils"
+)
+
+// identifierDateLayout is the layout of the dates in an identifier's validity window
+const identifierDateLayout = "2006-01-02"
+
+// FacilityIdentifierInput is used to add an identifier e.g an MFL or SHA code to a facility.
+// The identifier is valid from ValidFrom to ValidTo inclusive; a nil ValidTo means it does not expire.
+type FacilityIdentifierInput struct {
+	IdentifierType  FacilityIdentifierType `json:"identifier_type"`
+	IdentifierValue string                 `json:"identifier_value"`
+	ValidFrom       *scalarutils.Date      `json:"valid_from,omitempty"`
+	ValidTo         *scalarutils.Date      `json:"valid_to,omitempty"`
+}
+
+// Validate checks the identifier type and value and that the validity window is made of real dates in order
+func (i FacilityIdentifierInput) Validate() error {
+	if !i.IdentifierType.IsValid() {
+		return fmt.Errorf("invalid facility identifier type: %s", i.IdentifierType)
+	}
+
+	if i.IdentifierValue == "" {
+		return errors.New("identifier value must be provided")
+	}
+
+	if i.ValidFrom != nil {
+		if err := i.ValidFrom.Validate(); err != nil {
+			return fmt.Errorf("invalid valid from date: %w", err)
+		}
+	}
+
+	if i.ValidTo != nil {
+		if err := i.ValidTo.Validate(); err != nil {
+			return fmt.Errorf("invalid valid to date: %w", err)
+		}
+	}
+
+	if i.ValidFrom != nil && i.ValidTo != nil && i.ValidTo.AsTime().Before(i.ValidFrom.AsTime()) {
+		return errors.New("identifier cannot expire before it becomes valid")
+	}
+
+	return nil
+}
+
+// facilityIdentifierExpiryInput is used to set the last day on which an identifier is valid
+type facilityIdentifierExpiryInput struct {
+	ValidTo scalarutils.Date `json:"valid_to"`
+}
+
+// IsValidAt reports whether the identifier is valid on the day of t.
+// A missing valid from or valid to date leaves that end of the validity window open.
+func (i IdentifiersOutput) IsValidAt(t time.Time) bool {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	if i.ValidFrom != "" {
+		validFrom, err := parseIdentifierDate(i.ValidFrom)
+		if err != nil || day.Before(validFrom) {
+			return false
+		}
+	}
+
+	if i.ValidTo != "" {
+		validTo, err := parseIdentifierDate(i.ValidTo)
+		if err != nil || day.After(validTo) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseIdentifierDate reads the date part of an identifier's valid from or valid to value
+func parseIdentifierDate(value string) (time.Time, error) {
+	if len(value) > len(identifierDateLayout) {
+		value = value[:len(identifierDateLayout)]
+	}
+
+	return time.Parse(identifierDateLayout, value)
+}
+
+// facilityIdentifiersPath returns the path of a facility's identifiers
+func facilityIdentifiersPath(facilityID string) string {
+	return fmt.Sprintf("/v1/facilities/facilities/%s/identifiers/", facilityID)
+}
+
+// AddFacilityIdentifier adds an identifier to a facility
+func (h *HealthCRMLib) AddFacilityIdentifier(ctx context.Context, facilityID string, input FacilityIdentifierInput) (*IdentifiersOutput, error) {
+	if facilityID == "" {
+		return nil, errors.New("facility ID must be provided")
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	response, err := h.client.MakeRequest(ctx, http.MethodPost, facilityIdentifiersPath(facilityID), nil, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return readFacilityIdentifier(response, http.StatusCreated)
+}
+
+// ExpireFacilityIdentifier sets the last day on which one of a facility's identifiers is valid
+func (h *HealthCRMLib) ExpireFacilityIdentifier(ctx context.Context, facilityID, identifierID string, validTo scalarutils.Date) (*IdentifiersOutput, error) {
+	if facilityID == "" || identifierID == "" {
+		return nil, errors.New("facility ID and identifier ID must be provided")
+	}
+
+	if err := validTo.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid valid to date: %w", err)
+	}
+
+	path := fmt.Sprintf("%s%s/", facilityIdentifiersPath(facilityID), identifierID)
+
+	response, err := h.client.MakeRequest(ctx, http.MethodPatch, path, nil, facilityIdentifierExpiryInput{ValidTo: validTo})
+	if err != nil {
+		return nil, err
+	}
+
+	return readFacilityIdentifier(response, http.StatusOK)
+}
+
+// RotateFacilityIdentifier replaces a facility's current identifier of the given type e.g when SHA codes are migrated.
+// The new identifier is valid from effectiveFrom and the current one expires the day before. The current identifier
+// is only expired once the new one has been added, so a rejected code never leaves the facility without one.
+func (h *HealthCRMLib) RotateFacilityIdentifier(
+	ctx context.Context, facilityID string, identifierType FacilityIdentifierType, newValue string, effectiveFrom scalarutils.Date,
+) (*IdentifiersOutput, error) {
+	input := FacilityIdentifierInput{
+		IdentifierType:  identifierType,
+		IdentifierValue: newValue,
+		ValidFrom:       &effectiveFrom,
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	facility, err := h.GetFacilityByID(ctx, facilityID)
+	if err != nil {
+		return nil, err
+	}
+
+	lastDay := effectiveFrom.AsTime().AddDate(0, 0, -1)
+	validTo := scalarutils.Date{Year: lastDay.Year(), Month: int(lastDay.Month()), Day: lastDay.Day()}
+
+	var current []IdentifiersOutput
+
+	for _, identifier := range facility.Identifiers {
+		if identifier.IdentifierType != identifierType.String() || !identifier.IsValidAt(lastDay) {
+			continue
+		}
+
+		if identifier.IdentifierValue == newValue {
+			return nil, fmt.Errorf("facility already has the %s identifier %s", identifierType, newValue)
+		}
+
+		current = append(current, identifier)
+	}
+
+	added, err := h.AddFacilityIdentifier(ctx, facilityID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, identifier := range current {
+		if _, err := h.ExpireFacilityIdentifier(ctx, facilityID, identifier.ID, validTo); err != nil {
+			return nil, fmt.Errorf(
+				"added the %s identifier %s but unable to expire %s: %w", identifierType, newValue, identifier.IdentifierValue, err,
+			)
+		}
+	}
+
+	return added, nil
+}
+
+// GetFacilityByIdentifier fetches the facility of a CRM service that the identifier currently belongs to.
+// Facilities whose matching identifier has expired or is not valid yet are skipped, so after a code
+// is rotated the lookup returns the facility that holds it today. Every page of matches is checked.
+// It returns an error matching ErrNotFound when no facility currently holds the identifier.
+func (h *HealthCRMLib) GetFacilityByIdentifier(
+	ctx context.Context, crmServiceCode string, identifierType FacilityIdentifierType, value string,
+) (*FacilityOutput, error) {
+	if !identifierType.IsValid() {
+		return nil, fmt.Errorf("invalid facility identifier type: %s", identifierType)
+	}
+
+	if value == "" {
+		return nil, errors.New("identifier value must be provided")
+	}
+
+	filters := FilterFacilitiesInput{
+		CrmServiceCode:  crmServiceCode,
+		IdentifierType:  identifierType,
+		IdentifierValue: value,
+	}
+
+	now := time.Now()
+
+	for facility, err := range h.AllFacilities(ctx, filters) {
+		if err != nil {
+			return nil, err
+		}
+
+		for _, identifier := range facility.Identifiers {
+			if identifier.IdentifierType == identifierType.String() && identifier.IdentifierValue == value && identifier.IsValidAt(now) {
+				return &facility, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no facility currently holds the %s identifier %s: %w", identifierType, value, ErrNotFound)
+}
+
+// readFacilityIdentifier reads the identifier in a response that is expected to have the given status code
+func readFacilityIdentifier(response *http.Response, expectedStatusCode int) (*IdentifiersOutput, error) {
+	defer response.Body.Close()
+
+	respBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read response: %w", err)
+	}
+
+	if response.StatusCode != expectedStatusCode {
+		return nil, newResponseError(response, respBytes)
+	}
+
+	var identifier *IdentifiersOutput
+
+	err = json.Unmarshal(respBytes, &identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return identifier, nil
+}
@@ -0,0 +1,485 @@
+package healthcrm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/savannahghi/scalarutils"
+)
+
+// dateOf returns the scalarutils date of t
+func dateOf(t time.Time) scalarutils.Date {
+	return scalarutils.Date{Year: t.Year(), Month: int(t.Month()), Day: t.Day()}
+}
+
+func TestFacilityIdentifierInput_Validate(t *testing.T) {
+	from := scalarutils.Date{Year: 2024, Month: 7, Day: 1}
+	to := scalarutils.Date{Year: 2025, Month: 6, Day: 30}
+	invalid := scalarutils.Date{Year: 2024, Month: 13, Day: 1}
+
+	tests := []struct {
+		name    string
+		input   FacilityIdentifierInput
+		wantErr bool
+	}{
+		{
+			name:  "Happy case: open ended identifier",
+			input: FacilityIdentifierInput{IdentifierType: FacilityIdentifierTypeSHASladeCode, IdentifierValue: "SHA-123", ValidFrom: &from},
+		},
+		{
+			name:  "Happy case: bounded identifier",
+			input: FacilityIdentifierInput{IdentifierType: FacilityIdentifierTypeMFLCode, IdentifierValue: "12345", ValidFrom: &from, ValidTo: &to},
+		},
+		{
+			name:  "Happy case: single day identifier",
+			input: FacilityIdentifierInput{IdentifierType: FacilityIdentifierTypeMFLCode, IdentifierValue: "12345", ValidFrom: &from, ValidTo: &from},
+		},
+		{
+			name:    "Sad case: invalid type",
+			input:   FacilityIdentifierInput{IdentifierType: FacilityIdentifierType("NHIF"), IdentifierValue: "12345"},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: missing value",
+			input:   FacilityIdentifierInput{IdentifierType: FacilityIdentifierTypeMFLCode},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: invalid date",
+			input:   FacilityIdentifierInput{IdentifierType: FacilityIdentifierTypeMFLCode, IdentifierValue: "12345", ValidFrom: &invalid},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: expires before it is valid",
+			input:   FacilityIdentifierInput{IdentifierType: FacilityIdentifierTypeMFLCode, IdentifierValue: "12345", ValidFrom: &to, ValidTo: &from},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.input.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("FacilityIdentifierInput.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIdentifiersOutput_IsValidAt(t *testing.T) {
+	at := time.Date(2025, 3, 15, 18, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		identifier IdentifiersOutput
+		want       bool
+	}{
+		{
+			name:       "no validity window",
+			identifier: IdentifiersOutput{},
+			want:       true,
+		},
+		{
+			name:       "within the window",
+			identifier: IdentifiersOutput{ValidFrom: "2025-01-01", ValidTo: "2025-12-31"},
+			want:       true,
+		},
+		{
+			name:       "first day",
+			identifier: IdentifiersOutput{ValidFrom: "2025-03-15"},
+			want:       true,
+		},
+		{
+			name:       "last day",
+			identifier: IdentifiersOutput{ValidTo: "2025-03-15"},
+			want:       true,
+		},
+		{
+			name:       "date time values",
+			identifier: IdentifiersOutput{ValidFrom: "2025-01-01T00:00:00Z", ValidTo: "2025-03-15T00:00:00Z"},
+			want:       true,
+		},
+		{
+			name:       "not valid yet",
+			identifier: IdentifiersOutput{ValidFrom: "2025-03-16"},
+			want:       false,
+		},
+		{
+			name:       "expired",
+			identifier: IdentifiersOutput{ValidFrom: "2024-01-01", ValidTo: "2025-03-14"},
+			want:       false,
+		},
+		{
+			name:       "unreadable date",
+			identifier: IdentifiersOutput{ValidTo: "next year"},
+			want:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.identifier.IsValidAt(at); got != tt.want {
+				t.Errorf("IdentifiersOutput.IsValidAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHealthCRMLib_GetFacilityByIdentifier(t *testing.T) {
+	now := time.Now()
+	lastMonth := now.AddDate(0, -1, 0).Format(identifierDateLayout)
+	yesterday := now.AddDate(0, 0, -1).Format(identifierDateLayout)
+	today := now.Format(identifierDateLayout)
+
+	tests := []struct {
+		name           string
+		crmServiceCode string
+		identifierType FacilityIdentifierType
+		value          string
+		facilities     []FacilityOutput
+		wantID         string
+		wantErr        error
+		wantAnyErr     bool
+	}{
+		{
+			name:           "Happy case: current holder of a rotated code on a later page",
+			crmServiceCode: "50",
+			identifierType: FacilityIdentifierTypeSHASladeCode,
+			value:          "SHA-1",
+			facilities: []FacilityOutput{
+				{
+					ID: "old",
+					Identifiers: []IdentifiersOutput{
+						{IdentifierType: "SHA_SLADE_CODE", IdentifierValue: "SHA-1", ValidFrom: lastMonth, ValidTo: yesterday},
+					},
+				},
+				{
+					ID: "new",
+					Identifiers: []IdentifiersOutput{
+						{IdentifierType: "MFL_CODE", IdentifierValue: "SHA-1"},
+						{IdentifierType: "SHA_SLADE_CODE", IdentifierValue: "SHA-1", ValidFrom: today},
+					},
+				},
+			},
+			wantID: "new",
+		},
+		{
+			name:           "Sad case: identifier has expired",
+			crmServiceCode: "50",
+			identifierType: FacilityIdentifierTypeSHASladeCode,
+			value:          "SHA-1",
+			facilities: []FacilityOutput{
+				{
+					ID: "old",
+					Identifiers: []IdentifiersOutput{
+						{IdentifierType: "SHA_SLADE_CODE", IdentifierValue: "SHA-1", ValidTo: yesterday},
+					},
+				},
+			},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:           "Sad case: no facility",
+			crmServiceCode: "50",
+			identifierType: FacilityIdentifierTypeMFLCode,
+			value:          "12345",
+			wantErr:        ErrNotFound,
+		},
+		{
+			name:           "Sad case: missing CRM service code",
+			identifierType: FacilityIdentifierTypeMFLCode,
+			value:          "12345",
+			wantAnyErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			MockAuthenticate()
+
+			httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s/v1/facilities/facilities/", baseURL), func(r *http.Request) (*http.Response, error) {
+				query := r.URL.Query()
+				if query.Get("crm_service_code") != tt.crmServiceCode ||
+					query.Get("identifier_type") != tt.identifierType.String() || query.Get("identifier_value") != tt.value {
+					return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+				}
+
+				// one facility per page
+				page, _ := strconv.Atoi(query.Get("page"))
+				if page < 1 || page > len(tt.facilities) {
+					return httpmock.NewJsonResponse(http.StatusOK, &FacilityPage{})
+				}
+
+				result := &FacilityPage{Results: tt.facilities[page-1 : page]}
+				if page < len(tt.facilities) {
+					next := fmt.Sprintf("page=%d", page+1)
+					result.Next = &next
+				}
+
+				return httpmock.NewJsonResponse(http.StatusOK, result)
+			})
+
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
+			if err != nil {
+				t.Fatalf("unable to initialize sdk: %v", err)
+			}
+			defer h.Close()
+
+			facility, err := h.GetFacilityByIdentifier(context.Background(), tt.crmServiceCode, tt.identifierType, tt.value)
+			if tt.wantAnyErr {
+				if err == nil || errors.Is(err, ErrNotFound) {
+					t.Errorf("HealthCRMLib.GetFacilityByIdentifier() error = %v, want a validation error", err)
+				}
+
+				return
+			}
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("HealthCRMLib.GetFacilityByIdentifier() error = %v, want %v", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("HealthCRMLib.GetFacilityByIdentifier() error = %v", err)
+			}
+
+			if facility.ID != tt.wantID {
+				t.Errorf("HealthCRMLib.GetFacilityByIdentifier() = %s, want %s", facility.ID, tt.wantID)
+			}
+		})
+	}
+
+	t.Run("Sad case: invalid identifier type", func(t *testing.T) {
+		h := &HealthCRMLib{}
+
+		if _, err := h.GetFacilityByIdentifier(context.Background(), "50", FacilityIdentifierType("NHIF"), "123"); err == nil {
+			t.Errorf("HealthCRMLib.GetFacilityByIdentifier() expected an error for an invalid identifier type")
+		}
+	})
+}
+
+func TestHealthCRMLib_RotateFacilityIdentifier(t *testing.T) {
+	effective := scalarutils.Date{Year: 2025, Month: 7, Day: 1}
+	addPath := fmt.Sprintf("%s/v1/facilities/facilities/123/identifiers/", baseURL)
+	expirePath := fmt.Sprintf("%s/v1/facilities/facilities/123/identifiers/current/", baseURL)
+
+	tests := []struct {
+		name         string
+		addStatus    int
+		expireStatus int
+		wantCalls    []string
+		wantErr      bool
+	}{
+		{
+			name:         "Happy case: new identifier is added before the current one expires",
+			addStatus:    http.StatusCreated,
+			expireStatus: http.StatusOK,
+			wantCalls:    []string{"POST " + addPath, "PATCH " + expirePath},
+		},
+		{
+			name:      "Sad case: rejected identifier leaves the current one valid",
+			addStatus: http.StatusConflict,
+			wantCalls: []string{"POST " + addPath},
+			wantErr:   true,
+		},
+		{
+			name:         "Sad case: unable to expire the current identifier",
+			addStatus:    http.StatusCreated,
+			expireStatus: http.StatusBadRequest,
+			wantCalls:    []string{"POST " + addPath, "PATCH " + expirePath},
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			MockAuthenticate()
+
+			httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s/v1/facilities/facilities/123/", baseURL), httpmock.NewJsonResponderOrPanic(http.StatusOK, &FacilityOutput{
+				ID: "123",
+				Identifiers: []IdentifiersOutput{
+					{ID: "mfl", IdentifierType: "MFL_CODE", IdentifierValue: "12345"},
+					{ID: "expired", IdentifierType: "SHA_SLADE_CODE", IdentifierValue: "SHA-0", ValidTo: "2024-12-31"},
+					{ID: "current", IdentifierType: "SHA_SLADE_CODE", IdentifierValue: "SHA-1", ValidFrom: "2025-01-01"},
+				},
+			}))
+
+			var (
+				calls   []string
+				expired map[string]any
+				added   map[string]any
+			)
+
+			httpmock.RegisterResponder(http.MethodPatch, expirePath, func(r *http.Request) (*http.Response, error) {
+				calls = append(calls, "PATCH "+expirePath)
+
+				if err := json.NewDecoder(r.Body).Decode(&expired); err != nil {
+					return nil, err
+				}
+
+				return httpmock.NewJsonResponse(tt.expireStatus, &IdentifiersOutput{ID: "current", ValidTo: fmt.Sprint(expired["valid_to"])})
+			})
+
+			httpmock.RegisterResponder(http.MethodPost, addPath, func(r *http.Request) (*http.Response, error) {
+				calls = append(calls, "POST "+addPath)
+
+				if err := json.NewDecoder(r.Body).Decode(&added); err != nil {
+					return nil, err
+				}
+
+				return httpmock.NewJsonResponse(tt.addStatus, &IdentifiersOutput{ID: "rotated", IdentifierType: "SHA_SLADE_CODE", IdentifierValue: "SHA-2", ValidFrom: "2025-07-01"})
+			})
+
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
+			if err != nil {
+				t.Fatalf("unable to initialize sdk: %v", err)
+			}
+			defer h.Close()
+
+			identifier, err := h.RotateFacilityIdentifier(context.Background(), "123", FacilityIdentifierTypeSHASladeCode, "SHA-2", effective)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HealthCRMLib.RotateFacilityIdentifier() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Errorf("HealthCRMLib.RotateFacilityIdentifier() calls = %v, want %v", calls, tt.wantCalls)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if identifier.ID != "rotated" {
+				t.Errorf("HealthCRMLib.RotateFacilityIdentifier() = %+v, want the new identifier", identifier)
+			}
+
+			if expired["valid_to"] != "2025-06-30" {
+				t.Errorf("current identifier valid_to = %v, want the day before the new one is valid", expired["valid_to"])
+			}
+
+			if added["identifier_type"] != "SHA_SLADE_CODE" || added["identifier_value"] != "SHA-2" || added["valid_from"] != "2025-07-01" {
+				t.Errorf("added identifier = %v, want SHA-2 valid from 2025-07-01", added)
+			}
+		})
+	}
+}
+
+func TestHealthCRMLib_FacilityIdentifiers(t *testing.T) {
+	ctx := context.Background()
+	validFrom := dateOf(time.Now())
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		statusCode int
+		call       func(h *HealthCRMLib) (*IdentifiersOutput, error)
+		wantBody   map[string]any
+		wantErr    bool
+	}{
+		{
+			name:       "Happy case: add identifier",
+			method:     http.MethodPost,
+			path:       "/v1/facilities/facilities/123/identifiers/",
+			statusCode: http.StatusCreated,
+			call: func(h *HealthCRMLib) (*IdentifiersOutput, error) {
+				return h.AddFacilityIdentifier(ctx, "123", FacilityIdentifierInput{
+					IdentifierType:  FacilityIdentifierTypeMFLCode,
+					IdentifierValue: "12345",
+					ValidFrom:       &validFrom,
+				})
+			},
+			wantBody: map[string]any{"identifier_type": "MFL_CODE", "identifier_value": "12345", "valid_from": time.Now().Format(identifierDateLayout)},
+		},
+		{
+			name:       "Happy case: expire identifier",
+			method:     http.MethodPatch,
+			path:       "/v1/facilities/facilities/123/identifiers/456/",
+			statusCode: http.StatusOK,
+			call: func(h *HealthCRMLib) (*IdentifiersOutput, error) {
+				return h.ExpireFacilityIdentifier(ctx, "123", "456", scalarutils.Date{Year: 2025, Month: 6, Day: 30})
+			},
+			wantBody: map[string]any{"valid_to": "2025-06-30"},
+		},
+		{
+			name:       "Sad case: add identifier conflict",
+			method:     http.MethodPost,
+			path:       "/v1/facilities/facilities/123/identifiers/",
+			statusCode: http.StatusConflict,
+			call: func(h *HealthCRMLib) (*IdentifiersOutput, error) {
+				return h.AddFacilityIdentifier(ctx, "123", FacilityIdentifierInput{IdentifierType: FacilityIdentifierTypeMFLCode, IdentifierValue: "12345"})
+			},
+			wantErr: true,
+		},
+		{
+			name:   "Sad case: invalid expiry date is not sent",
+			method: http.MethodPatch,
+			path:   "/v1/facilities/facilities/123/identifiers/456/",
+			call: func(h *HealthCRMLib) (*IdentifiersOutput, error) {
+				return h.ExpireFacilityIdentifier(ctx, "123", "456", scalarutils.Date{})
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			MockAuthenticate()
+
+			var sent map[string]any
+
+			url := fmt.Sprintf("%s%s", baseURL, tt.path)
+			httpmock.RegisterResponder(tt.method, url, func(r *http.Request) (*http.Response, error) {
+				if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+					return nil, err
+				}
+
+				if tt.statusCode != http.StatusOK && tt.statusCode != http.StatusCreated {
+					return httpmock.NewStringResponse(tt.statusCode, `{"detail": "Identifier already exists."}`), nil
+				}
+
+				return httpmock.NewJsonResponse(tt.statusCode, &IdentifiersOutput{ID: "456", FacilityID: "123"})
+			})
+
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
+			if err != nil {
+				t.Fatalf("unable to initialize sdk: %v", err)
+			}
+			defer h.Close()
+
+			identifier, err := tt.call(h)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.statusCode == 0 {
+				if count := httpmock.GetCallCountInfo()[tt.method+" "+url]; count != 0 {
+					t.Errorf("invalid input sent %d requests, want none", count)
+				}
+
+				return
+			}
+
+			for key, value := range tt.wantBody {
+				if sent[key] != value {
+					t.Errorf("request body %s = %v, want %v", key, sent[key], value)
+				}
+			}
+
+			if !tt.wantErr && identifier.ID != "456" {
+				t.Errorf("identifier = %+v, want the identifier returned by health CRM", identifier)
+			}
+		})
+	}
+}
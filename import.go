@@ -0,0 +1,457 @@
+package healthcrm
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// defaultImportConcurrency is the number of rows imported at the same time when no concurrency is given
+const defaultImportConcurrency = 4
+
+// ImportFormat is the format of a facility import file
+type ImportFormat string
+
+// Import file formats
+const (
+	// ImportFormatCSV is a CSV file with a header row
+	ImportFormatCSV ImportFormat = "CSV"
+	// ImportFormatJSONL is a file with one flat JSON object per line
+	ImportFormatJSONL ImportFormat = "JSONL"
+)
+
+// ImportAction is what the importer did, or would do in a dry run, with a row
+type ImportAction string
+
+// Import actions
+const (
+	ImportActionCreate ImportAction = "CREATE"
+	ImportActionUpdate ImportAction = "UPDATE"
+	ImportActionSkip   ImportAction = "SKIP"
+	ImportActionFailed ImportAction = "FAILED"
+)
+
+// ColumnMapping names the CSV columns or JSONL keys that hold each facility field.
+// Fields mapped to an empty name are not imported. The MFL code column is required.
+type ColumnMapping struct {
+	MFLCode      string
+	Name         string
+	Description  string
+	FacilityType string
+	County       string
+	Country      string
+	Address      string
+	Latitude     string
+	Longitude    string
+	PhoneNumber  string
+	Email        string
+}
+
+// DefaultColumnMapping maps each facility field to a snake case column e.g mfl_code, facility_type
+func DefaultColumnMapping() ColumnMapping {
+	return ColumnMapping{
+		MFLCode:      "mfl_code",
+		Name:         "name",
+		Description:  "description",
+		FacilityType: "facility_type",
+		County:       "county",
+		Country:      "country",
+		Address:      "address",
+		Latitude:     "latitude",
+		Longitude:    "longitude",
+		PhoneNumber:  "phone_number",
+		Email:        "email",
+	}
+}
+
+// ImportOptions configures a facility import
+type ImportOptions struct {
+	Format ImportFormat
+	// Mapping defaults to DefaultColumnMapping
+	Mapping *ColumnMapping
+	// CrmServiceCode is used to look up the facilities that already exist
+	CrmServiceCode string
+	// Concurrency caps the number of rows imported at the same time. It defaults to 4.
+	Concurrency int
+	// DryRun looks up existing facilities and reports what would be done without creating or updating any
+	DryRun bool
+}
+
+// ImportRowResult is the outcome of importing one row
+type ImportRowResult struct {
+	// Line is the row's line number in the file
+	Line       int
+	MFLCode    string
+	Name       string
+	Action     ImportAction
+	FacilityID string
+	Err        error
+}
+
+// ImportReport lists the outcome of each row of an import, in file order
+type ImportReport struct {
+	DryRun  bool
+	Results []ImportRowResult
+}
+
+// Count returns the number of rows with the given action
+func (r *ImportReport) Count(action ImportAction) int {
+	count := 0
+
+	for _, result := range r.Results {
+		if result.Action == action {
+			count++
+		}
+	}
+
+	return count
+}
+
+// WriteCSV writes the report as CSV with one line per row
+func (r *ImportReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write([]string{"line", "mfl_code", "name", "action", "facility_id", "error"}); err != nil {
+		return err
+	}
+
+	for _, result := range r.Results {
+		var message string
+		if result.Err != nil {
+			message = result.Err.Error()
+		}
+
+		record := []string{strconv.Itoa(result.Line), result.MFLCode, result.Name, string(result.Action), result.FacilityID, message}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// importRow is a row read from an import file, keyed by column name
+type importRow struct {
+	line   int
+	values map[string]string
+}
+
+// readImportRows reads the rows of a CSV or JSONL import file
+func readImportRows(r io.Reader, format ImportFormat) ([]importRow, error) {
+	switch format {
+	case ImportFormatCSV:
+		return readCSVRows(r)
+	case ImportFormatJSONL:
+		return readJSONLRows(r)
+	default:
+		return nil, fmt.Errorf("invalid import format: %s", format)
+	}
+}
+
+// readCSVRows reads the rows of a CSV file whose first line names the columns
+func readCSVRows(r io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("could not read CSV header: %w", err)
+	}
+
+	var rows []importRow
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("could not read CSV: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		values := make(map[string]string, len(header))
+
+		for i, column := range header {
+			values[strings.TrimSpace(column)] = strings.TrimSpace(record[i])
+		}
+
+		rows = append(rows, importRow{line: line, values: values})
+	}
+}
+
+// readJSONLRows reads the rows of a file with a flat JSON object on each line. Blank lines are skipped.
+func readJSONLRows(r io.Reader) ([]importRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var rows []importRow
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var object map[string]any
+		if err := json.Unmarshal([]byte(text), &object); err != nil {
+			return nil, fmt.Errorf("could not read JSONL line %d: %w", line, err)
+		}
+
+		values := make(map[string]string, len(object))
+
+		for key, value := range object {
+			switch v := value.(type) {
+			case nil:
+			case string:
+				values[key] = strings.TrimSpace(v)
+			case float64:
+				values[key] = strconv.FormatFloat(v, 'f', -1, 64)
+			default:
+				values[key] = fmt.Sprint(v)
+			}
+		}
+
+		rows = append(rows, importRow{line: line, values: values})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read JSONL: %w", err)
+	}
+
+	return rows, nil
+}
+
+// facility builds the facility described by the row, returning its MFL code
+func (m ColumnMapping) facility(row importRow) (*Facility, string, error) {
+	get := func(column string) string {
+		if column == "" {
+			return ""
+		}
+
+		return row.values[column]
+	}
+
+	mflCode := get(m.MFLCode)
+	if mflCode == "" {
+		return nil, "", fmt.Errorf("missing MFL code in column %q", m.MFLCode)
+	}
+
+	facility := &Facility{
+		Name:         get(m.Name),
+		Description:  get(m.Description),
+		FacilityType: get(m.FacilityType),
+		County:       get(m.County),
+		Country:      get(m.Country),
+		Address:      get(m.Address),
+		Identifiers: []Identifiers{
+			{IdentifierType: FacilityIdentifierTypeMFLCode.String(), IdentifierValue: mflCode},
+		},
+	}
+
+	if facility.Name == "" {
+		return nil, mflCode, errors.New("missing facility name")
+	}
+
+	latitude, longitude := get(m.Latitude), get(m.Longitude)
+	if latitude != "" || longitude != "" {
+		coordinates := &Coordinates{Latitude: latitude, Longitude: longitude}
+		if _, err := coordinates.GeoPoint(); err != nil {
+			return nil, mflCode, err
+		}
+
+		facility.Coordinates = coordinates
+	}
+
+	contacts := []FacilityContactInput{
+		{ContactType: ContactTypePhoneNumber, ContactValue: get(m.PhoneNumber)},
+		{ContactType: ContactTypeEmail, ContactValue: get(m.Email)},
+	}
+
+	for _, contact := range contacts {
+		if contact.ContactValue == "" {
+			continue
+		}
+
+		if err := contact.Validate(); err != nil {
+			return nil, mflCode, err
+		}
+
+		facility.Contacts = append(facility.Contacts, Contacts{
+			ContactType:  contact.ContactType.String(),
+			ContactValue: contact.ContactValue,
+		})
+	}
+
+	return facility, mflCode, nil
+}
+
+// facilityUpdate returns a payload with the imported fields that differ from the existing facility.
+// Contacts and identifiers are only set when a facility is created; they have their own endpoints for edits.
+func facilityUpdate(imported *Facility, existing FacilityOutput) (*Facility, bool) {
+	fields := *imported
+	fields.Contacts, fields.Identifiers, fields.BusinessHours = nil, nil, nil
+
+	patch, _ := ReconcileFacility(fields, existing)
+
+	return patch, patch != nil
+}
+
+// ImportFacilities creates or updates the facilities in a CSV or JSONL file, e.g an MFL spreadsheet export.
+//
+// Each row is matched to an existing facility by its MFL code. Rows without a match are created, rows that
+// differ from their match are updated and the rest are skipped. A row that cannot be read or imported is
+// reported as failed without stopping the import. The returned error is only set when the file cannot be read.
+func (h *HealthCRMLib) ImportFacilities(ctx context.Context, r io.Reader, opts ImportOptions) (*ImportReport, error) {
+	if opts.CrmServiceCode == "" {
+		return nil, errors.New("CRM service code must be provided")
+	}
+
+	mapping := DefaultColumnMapping()
+	if opts.Mapping != nil {
+		mapping = *opts.Mapping
+	}
+
+	if mapping.MFLCode == "" || mapping.Name == "" {
+		return nil, errors.New("the MFL code and name columns must be mapped")
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultImportConcurrency
+	}
+
+	rows, err := readImportRows(r, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{
+		DryRun:  opts.DryRun,
+		Results: make([]ImportRowResult, len(rows)),
+	}
+
+	facilities := make([]*Facility, len(rows))
+	firstLines := map[string]int{}
+
+	for i, row := range rows {
+		result := &report.Results[i]
+		result.Line = row.line
+
+		facility, mflCode, err := mapping.facility(row)
+		result.MFLCode = mflCode
+
+		if facility != nil {
+			result.Name = facility.Name
+		}
+
+		if err != nil {
+			result.Action, result.Err = ImportActionFailed, err
+			continue
+		}
+
+		// importing the same facility twice at the same time could create it twice
+		if line, ok := firstLines[mflCode]; ok {
+			result.Action, result.Err = ImportActionFailed, fmt.Errorf("duplicate MFL code %s, first seen on line %d", mflCode, line)
+			continue
+		}
+
+		firstLines[mflCode] = row.line
+		facilities[i] = facility
+	}
+
+	// a fixed pool of workers imports the rows, so a large file does not start a goroutine per row
+	rowIndexes := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for range concurrency {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range rowIndexes {
+				result := &report.Results[i]
+
+				if err := ctx.Err(); err != nil {
+					result.Action, result.Err = ImportActionFailed, err
+					continue
+				}
+
+				result.Action, result.FacilityID, result.Err = h.importFacility(ctx, facilities[i], result.MFLCode, opts)
+				if result.Err != nil {
+					result.Action = ImportActionFailed
+				}
+			}
+		}()
+	}
+
+	for i, facility := range facilities {
+		if facility != nil {
+			rowIndexes <- i
+		}
+	}
+
+	close(rowIndexes)
+	wg.Wait()
+
+	return report, nil
+}
+
+// importFacility creates, updates or skips one facility, returning what was done and the facility's ID
+func (h *HealthCRMLib) importFacility(ctx context.Context, facility *Facility, mflCode string, opts ImportOptions) (ImportAction, string, error) {
+	page, err := h.GetFacilities(ctx, FilterFacilitiesInput{
+		CrmServiceCode:  opts.CrmServiceCode,
+		IdentifierType:  FacilityIdentifierTypeMFLCode,
+		IdentifierValue: mflCode,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("unable to look up MFL code %s: %w", mflCode, err)
+	}
+
+	switch len(page.Results) {
+	case 0:
+		if opts.DryRun {
+			return ImportActionCreate, "", nil
+		}
+
+		created, err := h.CreateFacility(ctx, facility)
+		if err != nil {
+			return "", "", err
+		}
+
+		return ImportActionCreate, created.ID, nil
+
+	case 1:
+		existing := page.Results[0]
+
+		update, changed := facilityUpdate(facility, existing)
+		if !changed {
+			return ImportActionSkip, existing.ID, nil
+		}
+
+		if opts.DryRun {
+			return ImportActionUpdate, existing.ID, nil
+		}
+
+		if _, err := h.UpdateFacility(ctx, existing.ID, update); err != nil {
+			return "", existing.ID, err
+		}
+
+		return ImportActionUpdate, existing.ID, nil
+
+	default:
+		return "", "", fmt.Errorf("%d facilities have the MFL code %s", len(page.Results), mflCode)
+	}
+}
@@ -0,0 +1,442 @@
+package healthcrm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestReadImportRows(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    ImportFormat
+		input     string
+		wantLines []int
+		wantName  string
+		wantLat   string
+		wantErr   bool
+	}{
+		{
+			name:      "Happy case: CSV",
+			format:    ImportFormatCSV,
+			input:     "mfl_code,name,latitude\n12345, Kenyatta National Hospital ,-1.30\n67890,Mbagathi,-1.31\n",
+			wantLines: []int{2, 3},
+			wantName:  "Kenyatta National Hospital",
+			wantLat:   "-1.30",
+		},
+		{
+			name:      "Happy case: JSONL with numbers and blank lines",
+			format:    ImportFormatJSONL,
+			input:     "{\"mfl_code\": \"12345\", \"name\": \"Kenyatta National Hospital\", \"latitude\": -1.3}\n\n{\"mfl_code\": 67890, \"name\": \"Mbagathi\", \"latitude\": null}\n",
+			wantLines: []int{1, 3},
+			wantName:  "Kenyatta National Hospital",
+			wantLat:   "-1.3",
+		},
+		{
+			name:    "Sad case: CSV row with missing columns",
+			format:  ImportFormatCSV,
+			input:   "mfl_code,name\n12345\n",
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: invalid JSONL",
+			format:  ImportFormatJSONL,
+			input:   "{\"mfl_code\": \"12345\"\n",
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: unknown format",
+			format:  ImportFormat("XLSX"),
+			input:   "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := readImportRows(strings.NewReader(tt.input), tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readImportRows() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if len(rows) != len(tt.wantLines) {
+				t.Fatalf("readImportRows() read %d rows, want %d", len(rows), len(tt.wantLines))
+			}
+
+			for i, line := range tt.wantLines {
+				if rows[i].line != line {
+					t.Errorf("readImportRows() row %d is on line %d, want %d", i, rows[i].line, line)
+				}
+			}
+
+			if rows[0].values["name"] != tt.wantName || rows[0].values["latitude"] != tt.wantLat {
+				t.Errorf("readImportRows() first row = %v", rows[0].values)
+			}
+
+			if rows[1].values["mfl_code"] != "67890" {
+				t.Errorf("readImportRows() second row MFL code = %q, want 67890", rows[1].values["mfl_code"])
+			}
+		})
+	}
+}
+
+func TestColumnMapping_facility(t *testing.T) {
+	mapping := ColumnMapping{
+		MFLCode:     "Code",
+		Name:        "Officialname",
+		County:      "County",
+		Latitude:    "Lat",
+		Longitude:   "Long",
+		PhoneNumber: "Phone",
+	}
+
+	tests := []struct {
+		name    string
+		values  map[string]string
+		wantErr bool
+	}{
+		{
+			name:   "Happy case: mapped columns",
+			values: map[string]string{"Code": "12345", "Officialname": "Kenyatta National Hospital", "County": "Nairobi", "Lat": "-1.30", "Long": "36.80", "Phone": "+254712345678"},
+		},
+		{
+			name:    "Sad case: missing MFL code",
+			values:  map[string]string{"Officialname": "Kenyatta National Hospital"},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: missing name",
+			values:  map[string]string{"Code": "12345"},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: swapped coordinates",
+			values:  map[string]string{"Code": "12345", "Officialname": "Kenyatta National Hospital", "Lat": "136.80", "Long": "-1.30"},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: invalid phone number",
+			values:  map[string]string{"Code": "12345", "Officialname": "Kenyatta National Hospital", "Phone": "n/a"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			facility, mflCode, err := mapping.facility(importRow{line: 2, values: tt.values})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ColumnMapping.facility() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if mflCode != "12345" || facility.Name != "Kenyatta National Hospital" || facility.County != "Nairobi" {
+				t.Errorf("ColumnMapping.facility() = %+v, %s", facility, mflCode)
+			}
+
+			if len(facility.Identifiers) != 1 || facility.Identifiers[0].IdentifierType != "MFL_CODE" || facility.Identifiers[0].IdentifierValue != "12345" {
+				t.Errorf("ColumnMapping.facility() identifiers = %+v", facility.Identifiers)
+			}
+
+			if len(facility.Contacts) != 1 || facility.Contacts[0].ContactType != "PHONE_NUMBER" {
+				t.Errorf("ColumnMapping.facility() contacts = %+v", facility.Contacts)
+			}
+
+			if facility.Coordinates == nil || facility.Coordinates.Latitude != "-1.30" {
+				t.Errorf("ColumnMapping.facility() coordinates = %+v", facility.Coordinates)
+			}
+		})
+	}
+}
+
+func TestFacilityUpdate(t *testing.T) {
+	existing := FacilityOutput{
+		ID:          "123",
+		Name:        "Mbagathi",
+		County:      "Nairobi",
+		Coordinates: CoordinatesOutput{Latitude: -1.31, Longitude: 36.8},
+	}
+
+	tests := []struct {
+		name        string
+		imported    *Facility
+		want        *Facility
+		wantChanged bool
+	}{
+		{
+			name:     "Happy case: unchanged",
+			imported: &Facility{Name: "Mbagathi", County: "Nairobi", Coordinates: &Coordinates{Latitude: "-1.310001", Longitude: "36.80"}},
+		},
+		{
+			name:        "Happy case: changed name",
+			imported:    &Facility{Name: "Mbagathi County Hospital", County: "Nairobi"},
+			want:        &Facility{Name: "Mbagathi County Hospital"},
+			wantChanged: true,
+		},
+		{
+			name:        "Happy case: moved",
+			imported:    &Facility{Name: "Mbagathi", Coordinates: &Coordinates{Latitude: "-1.32", Longitude: "36.80"}},
+			want:        &Facility{Coordinates: &Coordinates{Latitude: "-1.32", Longitude: "36.80"}},
+			wantChanged: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := facilityUpdate(tt.imported, existing)
+			if changed != tt.wantChanged {
+				t.Errorf("facilityUpdate() changed = %v, want %v", changed, tt.wantChanged)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("facilityUpdate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// registerImportResponders mocks health CRM with the existing facilities keyed by MFL code.
+// It returns a function that reports the MFL codes that were created and the facility IDs that were updated.
+func registerImportResponders(existing map[string][]FacilityOutput) func() ([]string, []string) {
+	facilitiesPath := fmt.Sprintf("%s/v1/facilities/facilities/", baseURL)
+
+	var (
+		mu      sync.Mutex
+		created []string
+		updated []string
+	)
+
+	httpmock.RegisterResponder(http.MethodGet, facilitiesPath, func(r *http.Request) (*http.Response, error) {
+		if r.URL.Query().Get("identifier_type") != "MFL_CODE" {
+			return httpmock.NewStringResponse(http.StatusBadRequest, `{"identifier_type": ["Required."]}`), nil
+		}
+
+		return httpmock.NewJsonResponse(http.StatusOK, &FacilityPage{Results: existing[r.URL.Query().Get("identifier_value")]})
+	})
+
+	httpmock.RegisterResponder(http.MethodPost, facilitiesPath, func(r *http.Request) (*http.Response, error) {
+		var facility Facility
+		if err := json.NewDecoder(r.Body).Decode(&facility); err != nil {
+			return nil, err
+		}
+
+		if facility.Name == "Rejected" {
+			return httpmock.NewStringResponse(http.StatusBadRequest, `{"name": ["Not allowed."]}`), nil
+		}
+
+		mu.Lock()
+		created = append(created, facility.Identifiers[0].IdentifierValue)
+		mu.Unlock()
+
+		return httpmock.NewJsonResponse(http.StatusCreated, &FacilityOutput{ID: "new-" + facility.Identifiers[0].IdentifierValue, Name: facility.Name})
+	})
+
+	httpmock.RegisterRegexpResponder(http.MethodPatch, regexp.MustCompile(regexp.QuoteMeta(facilitiesPath)+`[^/]+/$`), func(r *http.Request) (*http.Response, error) {
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/facilities/facilities/"), "/")
+
+		mu.Lock()
+		updated = append(updated, id)
+		mu.Unlock()
+
+		return httpmock.NewJsonResponse(http.StatusOK, &FacilityOutput{ID: id})
+	})
+
+	return func() ([]string, []string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return created, updated
+	}
+}
+
+func TestHealthCRMLib_ImportFacilities(t *testing.T) {
+	ctx := context.Background()
+
+	existing := map[string][]FacilityOutput{
+		"11111": {{ID: "unchanged", Name: "Mbagathi", County: "Nairobi"}},
+		"22222": {{ID: "renamed", Name: "Old Name", County: "Nairobi"}},
+		"44444": {{ID: "a", Name: "Twin"}, {ID: "b", Name: "Twin"}},
+	}
+
+	file := `mfl_code,name,county
+11111,Mbagathi,Nairobi
+22222,New Name,Nairobi
+33333,Brand New,Kiambu
+,No Code,Kiambu
+44444,Twin,Nairobi
+33333,Brand New Again,Kiambu
+55555,Rejected,Kiambu
+`
+
+	tests := []struct {
+		name        string
+		opts        ImportOptions
+		wantActions []ImportAction
+		wantCreated int
+		wantUpdated int
+	}{
+		{
+			name: "Happy case: import",
+			opts: ImportOptions{Format: ImportFormatCSV, CrmServiceCode: "05", Concurrency: 2},
+			wantActions: []ImportAction{
+				ImportActionSkip, ImportActionUpdate, ImportActionCreate, ImportActionFailed,
+				ImportActionFailed, ImportActionFailed, ImportActionFailed,
+			},
+			wantCreated: 1,
+			wantUpdated: 1,
+		},
+		{
+			name: "Happy case: dry run",
+			opts: ImportOptions{Format: ImportFormatCSV, CrmServiceCode: "05", DryRun: true},
+			wantActions: []ImportAction{
+				ImportActionSkip, ImportActionUpdate, ImportActionCreate, ImportActionFailed,
+				ImportActionFailed, ImportActionFailed, ImportActionCreate,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			MockAuthenticate()
+
+			calls := registerImportResponders(existing)
+
+			h, err := NewHealthCRMLib(testOptions(baseURL, WithRetryPolicy(NoRetryPolicy()))...)
+			if err != nil {
+				t.Fatalf("unable to initialize sdk: %v", err)
+			}
+			defer h.Close()
+
+			report, err := h.ImportFacilities(ctx, strings.NewReader(file), tt.opts)
+			if err != nil {
+				t.Fatalf("HealthCRMLib.ImportFacilities() error = %v", err)
+			}
+
+			if len(report.Results) != len(tt.wantActions) {
+				t.Fatalf("HealthCRMLib.ImportFacilities() reported %d rows, want %d", len(report.Results), len(tt.wantActions))
+			}
+
+			for i, want := range tt.wantActions {
+				result := report.Results[i]
+				if result.Action != want || result.Line != i+2 {
+					t.Errorf("row %d = %s on line %d (%v), want %s on line %d", i, result.Action, result.Line, result.Err, want, i+2)
+				}
+
+				if (result.Err != nil) != (want == ImportActionFailed) {
+					t.Errorf("row %d error = %v", i, result.Err)
+				}
+			}
+
+			if report.Results[1].FacilityID != "renamed" {
+				t.Errorf("updated row facility ID = %s, want renamed", report.Results[1].FacilityID)
+			}
+
+			created, updated := calls()
+			if len(created) != tt.wantCreated || len(updated) != tt.wantUpdated {
+				t.Errorf("HealthCRMLib.ImportFacilities() created %v and updated %v", created, updated)
+			}
+
+			if report.DryRun != tt.opts.DryRun || report.Count(ImportActionSkip) != 1 {
+				t.Errorf("HealthCRMLib.ImportFacilities() report = %+v", report)
+			}
+		})
+	}
+}
+
+func TestHealthCRMLib_ImportFacilities_Concurrency(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	MockAuthenticate()
+
+	var inFlight, maxInFlight, maxGoroutines atomic.Int32
+
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s/v1/facilities/facilities/", baseURL), func(r *http.Request) (*http.Response, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for goroutines := int32(runtime.NumGoroutine()); ; {
+			current := maxGoroutines.Load()
+			if goroutines <= current || maxGoroutines.CompareAndSwap(current, goroutines) {
+				break
+			}
+		}
+
+		for {
+			current := maxInFlight.Load()
+			if n <= current || maxInFlight.CompareAndSwap(current, n) {
+				break
+			}
+		}
+
+		return httpmock.NewJsonResponse(http.StatusOK, &FacilityPage{Results: []FacilityOutput{{ID: "1", Name: "Clinic"}}})
+	})
+
+	var file strings.Builder
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&file, "{\"mfl_code\": \"%d\", \"name\": \"Clinic\"}\n", 10000+i)
+	}
+
+	h, err := NewHealthCRMLib(testOptions(baseURL)...)
+	if err != nil {
+		t.Fatalf("unable to initialize sdk: %v", err)
+	}
+	defer h.Close()
+
+	goroutinesBefore := int32(runtime.NumGoroutine())
+
+	report, err := h.ImportFacilities(context.Background(), strings.NewReader(file.String()), ImportOptions{
+		Format:         ImportFormatJSONL,
+		CrmServiceCode: "05",
+		Concurrency:    3,
+	})
+	if err != nil {
+		t.Fatalf("HealthCRMLib.ImportFacilities() error = %v", err)
+	}
+
+	if report.Count(ImportActionSkip) != 200 {
+		t.Errorf("HealthCRMLib.ImportFacilities() skipped %d rows, want 200", report.Count(ImportActionSkip))
+	}
+
+	if maxInFlight.Load() > 3 {
+		t.Errorf("HealthCRMLib.ImportFacilities() made %d requests at the same time, want at most 3", maxInFlight.Load())
+	}
+
+	// the 3 workers, with some slack for the runtime, rather than one goroutine per row
+	if started := maxGoroutines.Load() - goroutinesBefore; started > 50 {
+		t.Errorf("HealthCRMLib.ImportFacilities() started %d goroutines for 200 rows, want a fixed pool", started)
+	}
+}
+
+func TestImportReport_WriteCSV(t *testing.T) {
+	report := &ImportReport{
+		Results: []ImportRowResult{
+			{Line: 2, MFLCode: "12345", Name: "Mbagathi", Action: ImportActionCreate, FacilityID: "123"},
+			{Line: 3, Name: "No Code", Action: ImportActionFailed, Err: fmt.Errorf("missing MFL code")},
+		},
+	}
+
+	var out bytes.Buffer
+	if err := report.WriteCSV(&out); err != nil {
+		t.Fatalf("ImportReport.WriteCSV() error = %v", err)
+	}
+
+	want := "line,mfl_code,name,action,facility_id,error\n2,12345,Mbagathi,CREATE,123,\n3,,No Code,FAILED,,missing MFL code\n"
+	if out.String() != want {
+		t.Errorf("ImportReport.WriteCSV() = %q, want %q", out.String(), want)
+	}
+}
@@ -1,6 +1,7 @@
 package healthcrm
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/savannahghi/scalarutils"
@@ -21,9 +22,46 @@ type Facility struct {
 	BusinessHours []BusinessHours `json:"businesshours,omitempty"`
 }
 
+// MarshalJSON leaves out nil lists but sends empty ones as [], so that an update can remove all of a facility's
+// contacts, identifiers or business hours
+func (f Facility) MarshalJSON() ([]byte, error) {
+	type facility Facility
+
+	payload := struct {
+		facility
+		Contacts      *[]Contacts      `json:"contacts,omitempty"`
+		Identifiers   *[]Identifiers   `json:"identifiers,omitempty"`
+		BusinessHours *[]BusinessHours `json:"businesshours,omitempty"`
+	}{facility: facility(f)}
+
+	if f.Contacts != nil {
+		payload.Contacts = &f.Contacts
+	}
+
+	if f.Identifiers != nil {
+		payload.Identifiers = &f.Identifiers
+	}
+
+	if f.BusinessHours != nil {
+		payload.BusinessHours = &f.BusinessHours
+	}
+
+	return json.Marshal(payload)
+}
+
+// facilityStatusInput is used to change a facility's status
+type facilityStatusInput struct {
+	Status FacilityStatus `json:"status"`
+}
+
 // FilterFacilitiesInput takes in the parameters to filter facilities
 type FilterFacilitiesInput struct {
-	Location        *Coordinates
+	// Location takes the latitude, longitude and radius as strings; prefer Near and Radius
+	Location *Coordinates
+	// Near orders the facilities by their proximity to a point
+	Near *GeoPoint
+	// Radius limits the results to facilities within that distance of Near e.g 10 * Kilometer
+	Radius          Distance
 	ServiceIDs      []string
 	SearchParameter string
 	Pagination      *Pagination
@@ -46,15 +84,21 @@ type Coordinates struct {
 }
 
 // ToString returns the location in comma-separated values format.
-// The order of values in the string is longitude,latitude.
-// The latitude and longitude are formatted up to 5 decimal places.
-// For example, if the Location has Latitude 36.79 and Longitude -1.29,
-// the returned string will be "-1.29, 36.79".
+// The order of values in the string is longitude,latitude, the order health CRM expects.
+// The latitude and longitude are range checked and formatted up to 5 decimal places.
+// For example, if the Location has Latitude -1.29 and Longitude 36.79,
+// the returned string will be "36.79, -1.29".
 func (c Coordinates) ToString() (string, error) {
 	if c.Latitude == "" || c.Longitude == "" {
 		return "", fmt.Errorf("both Latitude and Longitude must be provided to generate the coordinates string")
 	}
-	return fmt.Sprintf("%v, %v", c.Longitude, c.Latitude), nil
+
+	point, err := c.GeoPoint()
+	if err != nil {
+		return "", err
+	}
+
+	return point.refLocation(), nil
 }
 
 // Contacts models facility's model data class
@@ -79,10 +123,11 @@ type BusinessHours struct {
 	ClosingTime string `json:"closing_time"`
 }
 
-// Pagination is used to hold pagination values
+// Pagination is used to hold pagination values.
+// A zero Page or PageSize is left for health CRM to default.
 type Pagination struct {
-	Page     string `json:"page"`
-	PageSize string `json:"page_size"`
+	Page     int `json:"page"`
+	PageSize int `json:"page_size"`
 }
 
 // FacilityServiceInput models is used to create a new service
@@ -94,8 +139,8 @@ type FacilityServiceInput struct {
 
 // ServiceIdentifierInput is used to create an identifier
 type ServiceIdentifierInput struct {
-	IdentifierType  string `json:"identifier_type"`
-	IdentifierValue string `json:"identifier_value"`
+	IdentifierType  ServiceIdentifierType `json:"identifier_type"`
+	IdentifierValue string                `json:"identifier_value"`
 }
 
 // ProfileInput is the host of users data or a brief description of a person
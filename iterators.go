@@ -0,0 +1,144 @@
+package healthcrm
+
+import (
+	"context"
+	"iter"
+)
+
+// defaultIterPageSize is the number of items requested per page when no page size hint is given
+const defaultIterPageSize = 50
+
+// iterOptions controls how the All* iterators walk the pages of a listing
+type iterOptions struct {
+	pageSize int
+	maxItems int
+}
+
+// IterOption configures an All* iterator
+type IterOption func(*iterOptions)
+
+// WithPageSize sets the number of items requested per page. Health CRM may cap it.
+func WithPageSize(size int) IterOption {
+	return func(o *iterOptions) {
+		if size > 0 {
+			o.pageSize = size
+		}
+	}
+}
+
+// WithMaxItems stops the iteration once max items have been returned
+func WithMaxItems(maxItems int) IterOption {
+	return func(o *iterOptions) {
+		if maxItems > 0 {
+			o.maxItems = maxItems
+		}
+	}
+}
+
+// fetchPageFunc fetches one page of a listing
+type fetchPageFunc[T any] func(ctx context.Context, pagination *Pagination) (*Page[T], error)
+
+// paginate returns an iterator that fetches pages lazily, starting from the first one.
+// It stops after the last page, the maximum number of items, the first error or when ctx is done.
+func paginate[T any](ctx context.Context, fetch fetchPageFunc[T], opts []IterOption) iter.Seq2[T, error] {
+	o := iterOptions{pageSize: defaultIterPageSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		count := 0
+
+		for page := 1; ; page++ {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			result, err := fetch(ctx, &Pagination{Page: page, PageSize: o.pageSize})
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range result.Results {
+				if o.maxItems > 0 && count >= o.maxItems {
+					return
+				}
+
+				if err := ctx.Err(); err != nil {
+					yield(zero, err)
+					return
+				}
+
+				if !yield(item, nil) {
+					return
+				}
+
+				count++
+			}
+
+			if !result.HasNext() || len(result.Results) == 0 || (o.maxItems > 0 && count >= o.maxItems) {
+				return
+			}
+		}
+	}
+}
+
+// collect reads every item of an iterator into a slice, stopping at the first error
+func collect[T any](items iter.Seq2[T, error]) ([]T, error) {
+	var all []T
+
+	for item, err := range items {
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, item)
+	}
+
+	return all, nil
+}
+
+// AllFacilities returns an iterator over every facility matching filters, fetching pages as they are needed.
+// The filters' pagination is ignored; use WithPageSize and WithMaxItems instead.
+func (h *HealthCRMLib) AllFacilities(ctx context.Context, filters FilterFacilitiesInput, opts ...IterOption) iter.Seq2[FacilityOutput, error] {
+	return paginate(ctx, func(ctx context.Context, pagination *Pagination) (*Page[FacilityOutput], error) {
+		filters.Pagination = pagination
+
+		return h.GetFacilities(ctx, filters)
+	}, opts)
+}
+
+// AllFacilitiesOfferingAService returns an iterator over every facility that offers a service
+func (h *HealthCRMLib) AllFacilitiesOfferingAService(ctx context.Context, serviceID string, opts ...IterOption) iter.Seq2[FacilityOutput, error] {
+	return paginate(ctx, func(ctx context.Context, pagination *Pagination) (*Page[FacilityOutput], error) {
+		return h.GetFacilitiesOfferingAService(ctx, serviceID, pagination)
+	}, opts)
+}
+
+// AllServices returns an iterator over every service owned by a CRM service
+func (h *HealthCRMLib) AllServices(ctx context.Context, crmServiceCode string, opts ...IterOption) iter.Seq2[FacilityService, error] {
+	return paginate(ctx, func(ctx context.Context, pagination *Pagination) (*Page[FacilityService], error) {
+		return h.GetServices(ctx, pagination, crmServiceCode)
+	}, opts)
+}
+
+// AllPractitioners returns an iterator over every practitioner matching filters.
+// The filters' pagination is ignored; use WithPageSize and WithMaxItems instead.
+func (h *HealthCRMLib) AllPractitioners(ctx context.Context, filters FilterPractitionersInput, opts ...IterOption) iter.Seq2[Practitioner, error] {
+	return paginate(ctx, func(ctx context.Context, pagination *Pagination) (*Page[Practitioner], error) {
+		filters.Pagination = pagination
+
+		return h.GetPractitioners(ctx, filters)
+	}, opts)
+}
+
+// AllSpecialties returns an iterator over every specialty owned by a CRM service
+func (h *HealthCRMLib) AllSpecialties(ctx context.Context, crmServiceCode string, opts ...IterOption) iter.Seq2[PractitionerSpecialty, error] {
+	return paginate(ctx, func(ctx context.Context, pagination *Pagination) (*Page[PractitionerSpecialty], error) {
+		return h.GetSpecialties(ctx, pagination, crmServiceCode)
+	}, opts)
+}
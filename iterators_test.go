@@ -0,0 +1,296 @@
+package healthcrm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// fakePages serves pages of consecutive integers and counts the fetches
+type fakePages struct {
+	total     int
+	fetches   int
+	failPage  int
+	pageSizes []int
+}
+
+func (f *fakePages) fetch(ctx context.Context, pagination *Pagination) (*Page[int], error) {
+	f.fetches++
+	f.pageSizes = append(f.pageSizes, pagination.PageSize)
+
+	page, size := pagination.Page, pagination.PageSize
+
+	if page == f.failPage {
+		return nil, errors.New("page failed")
+	}
+
+	result := &Page[int]{Count: f.total, CurrentPage: page, PageSize: size}
+	for i := (page - 1) * size; i < min(page*size, f.total); i++ {
+		result.Results = append(result.Results, i)
+	}
+
+	if page*size < f.total {
+		next := fmt.Sprintf("https://healthcrm.test/v1/items/?page=%d", page+1)
+		result.Next = &next
+	}
+
+	return result, nil
+}
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		name        string
+		pages       *fakePages
+		opts        []IterOption
+		breakAfter  int
+		wantItems   int
+		wantFetches int
+		wantErr     bool
+	}{
+		{
+			name:        "Happy case: walks every page",
+			pages:       &fakePages{total: 7},
+			opts:        []IterOption{WithPageSize(3)},
+			wantItems:   7,
+			wantFetches: 3,
+		},
+		{
+			name:        "Happy case: empty listing",
+			pages:       &fakePages{total: 0},
+			wantItems:   0,
+			wantFetches: 1,
+		},
+		{
+			name:        "Happy case: max items stops fetching",
+			pages:       &fakePages{total: 100},
+			opts:        []IterOption{WithPageSize(3), WithMaxItems(5)},
+			wantItems:   5,
+			wantFetches: 2,
+		},
+		{
+			name:        "Happy case: max items on a page boundary",
+			pages:       &fakePages{total: 100},
+			opts:        []IterOption{WithPageSize(3), WithMaxItems(6)},
+			wantItems:   6,
+			wantFetches: 2,
+		},
+		{
+			name:        "Happy case: caller stops early",
+			pages:       &fakePages{total: 100},
+			opts:        []IterOption{WithPageSize(3)},
+			breakAfter:  4,
+			wantItems:   4,
+			wantFetches: 2,
+		},
+		{
+			name:        "Sad case: page fails",
+			pages:       &fakePages{total: 100, failPage: 2},
+			opts:        []IterOption{WithPageSize(3)},
+			wantItems:   3,
+			wantFetches: 2,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := 0
+
+			var gotErr error
+			for item, err := range paginate(context.Background(), tt.pages.fetch, tt.opts) {
+				if err != nil {
+					gotErr = err
+					break
+				}
+
+				if item != items {
+					t.Errorf("paginate() item = %d, want %d", item, items)
+				}
+
+				items++
+				if items == tt.breakAfter {
+					break
+				}
+			}
+
+			if (gotErr != nil) != tt.wantErr {
+				t.Errorf("paginate() error = %v, wantErr %v", gotErr, tt.wantErr)
+			}
+
+			if items != tt.wantItems {
+				t.Errorf("paginate() returned %d items, want %d", items, tt.wantItems)
+			}
+
+			if tt.pages.fetches != tt.wantFetches {
+				t.Errorf("paginate() fetched %d pages, want %d", tt.pages.fetches, tt.wantFetches)
+			}
+		})
+	}
+}
+
+func TestPaginate_DefaultPageSize(t *testing.T) {
+	pages := &fakePages{total: 1}
+
+	for range paginate(context.Background(), pages.fetch, nil) {
+	}
+
+	if len(pages.pageSizes) != 1 || pages.pageSizes[0] != defaultIterPageSize {
+		t.Errorf("paginate() page sizes = %v, want %d", pages.pageSizes, defaultIterPageSize)
+	}
+}
+
+func TestPaginate_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pages := &fakePages{total: 100}
+
+	items := 0
+
+	var gotErr error
+	for _, err := range paginate(ctx, pages.fetch, []IterOption{WithPageSize(3)}) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+
+		items++
+		if items == 2 {
+			cancel()
+		}
+	}
+
+	if !errors.Is(gotErr, context.Canceled) {
+		t.Errorf("paginate() error = %v, want %v", gotErr, context.Canceled)
+	}
+
+	if items != 2 || pages.fetches != 1 {
+		t.Errorf("paginate() returned %d items from %d pages, want 2 items from 1 page", items, pages.fetches)
+	}
+}
+
+// registerPagedResponder serves two pages of results with a next link on the first one
+func registerPagedResponder(path string, results func(page string) string) {
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s%s", baseURL, path), func(req *http.Request) (*http.Response, error) {
+		page := req.URL.Query().Get("page")
+
+		next := "null"
+		if page == "1" {
+			next = fmt.Sprintf(`"%s%s?page=2"`, baseURL, path)
+		}
+
+		body := fmt.Sprintf(`{"count": 2, "next": %s, "previous": null, "results": [%s]}`, next, results(page))
+
+		return httpmock.NewStringResponse(http.StatusOK, body), nil
+	})
+}
+
+func TestHealthCRMLib_AllIterators(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		path string
+		ids  func(h *HealthCRMLib) ([]string, error)
+	}{
+		{
+			name: "AllFacilities",
+			path: "/v1/facilities/facilities/",
+			ids: func(h *HealthCRMLib) ([]string, error) {
+				var ids []string
+				for facility, err := range h.AllFacilities(ctx, FilterFacilitiesInput{CrmServiceCode: "05"}) {
+					if err != nil {
+						return nil, err
+					}
+					ids = append(ids, facility.ID)
+				}
+				return ids, nil
+			},
+		},
+		{
+			name: "AllFacilitiesOfferingAService",
+			path: "/v1/facilities/facilities/",
+			ids: func(h *HealthCRMLib) ([]string, error) {
+				var ids []string
+				for facility, err := range h.AllFacilitiesOfferingAService(ctx, "123") {
+					if err != nil {
+						return nil, err
+					}
+					ids = append(ids, facility.ID)
+				}
+				return ids, nil
+			},
+		},
+		{
+			name: "AllServices",
+			path: "/v1/facilities/services/",
+			ids: func(h *HealthCRMLib) ([]string, error) {
+				var ids []string
+				for service, err := range h.AllServices(ctx, "05") {
+					if err != nil {
+						return nil, err
+					}
+					ids = append(ids, service.ID)
+				}
+				return ids, nil
+			},
+		},
+		{
+			name: "AllPractitioners",
+			path: "/v1/practitioners/practitioners/",
+			ids: func(h *HealthCRMLib) ([]string, error) {
+				var ids []string
+				for practitioner, err := range h.AllPractitioners(ctx, FilterPractitionersInput{CrmServiceCode: "05"}) {
+					if err != nil {
+						return nil, err
+					}
+					ids = append(ids, practitioner.ID)
+				}
+				return ids, nil
+			},
+		},
+		{
+			name: "AllSpecialties",
+			path: "/v1/practitioners/specialties/",
+			ids: func(h *HealthCRMLib) ([]string, error) {
+				var ids []string
+				for specialty, err := range h.AllSpecialties(ctx, "05") {
+					if err != nil {
+						return nil, err
+					}
+					ids = append(ids, specialty.ID)
+				}
+				return ids, nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			MockAuthenticate()
+
+			registerPagedResponder(tt.path, func(page string) string {
+				return fmt.Sprintf(`{"id": "page-%s"}`, page)
+			})
+
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
+			if err != nil {
+				t.Fatalf("unable to initialize sdk: %v", err)
+			}
+			defer h.Close()
+
+			ids, err := tt.ids(h)
+			if err != nil {
+				t.Fatalf("HealthCRMLib.%s() error = %v", tt.name, err)
+			}
+
+			if len(ids) != 2 || ids[0] != "page-1" || ids[1] != "page-2" {
+				t.Errorf("HealthCRMLib.%s() = %v, want the results of both pages", tt.name, ids)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package healthcrm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/savannahghi/authutils"
+	"github.com/savannahghi/serverutils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultHTTPTimeout        = 10 * time.Second
+	defaultTokenRefreshMargin = 1 * time.Minute
+)
+
+// options holds the configuration used to build a HealthCRMLib instance
+type options struct {
+	baseURL    string
+	authConfig authutils.Config
+	httpClient *http.Client
+	logger     logrus.FieldLogger
+
+	tokenSource        TokenSource
+	tokenRefreshMargin time.Duration
+	tokenRefreshJitter time.Duration
+	retryPolicy        RetryPolicy
+	rateLimit          RateLimit
+	endpointRateLimits map[EndpointGroup]RateLimit
+}
+
+// Option configures a HealthCRMLib instance when passed to NewHealthCRMLib
+type Option func(*options) error
+
+// defaultOptions returns the options used when none are provided
+func defaultOptions() *options {
+	return &options{
+		httpClient: &http.Client{
+			Timeout: defaultHTTPTimeout,
+		},
+		logger:             logrus.StandardLogger(),
+		tokenRefreshMargin: defaultTokenRefreshMargin,
+		retryPolicy:        DefaultRetryPolicy(),
+	}
+}
+
+// validate checks that the options contain what is needed to talk to health CRM
+func (o *options) validate() error {
+	if o.baseURL == "" {
+		return errors.New("health CRM base URL must be provided")
+	}
+
+	if o.httpClient == nil {
+		return errors.New("http client must not be nil")
+	}
+
+	if o.logger == nil {
+		return errors.New("logger must not be nil")
+	}
+
+	return nil
+}
+
+// WithEnvConfig reads the base URL, auth server endpoint, credentials and grant type
+// from the HEALTH_CRM_* environment variables. Options passed after it take precedence.
+func WithEnvConfig() Option {
+	return func(o *options) error {
+		envVars := []struct {
+			name   string
+			target *string
+		}{
+			{name: "HEALTH_CRM_BASE_URL", target: &o.baseURL},
+			{name: "HEALTH_CRM_AUTH_SERVER_ENDPOINT", target: &o.authConfig.AuthServerEndpoint},
+			{name: "HEALTH_CRM_CLIENT_ID", target: &o.authConfig.ClientID},
+			{name: "HEALTH_CRM_CLIENT_SECRET", target: &o.authConfig.ClientSecret},
+			{name: "HEALTH_CRM_GRANT_TYPE", target: &o.authConfig.GrantType},
+			{name: "HEALTH_CRM_USERNAME", target: &o.authConfig.Username},
+			{name: "HEALTH_CRM_PASSWORD", target: &o.authConfig.Password},
+		}
+
+		for _, envVar := range envVars {
+			value, err := serverutils.GetEnvVar(envVar.name)
+			if err != nil {
+				return err
+			}
+
+			*envVar.target = value
+		}
+
+		o.baseURL = strings.TrimSuffix(o.baseURL, "/")
+
+		return nil
+	}
+}
+
+// WithBaseURL sets the health CRM base URL e.g https://healthcrm.example.com
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) error {
+		if baseURL == "" {
+			return errors.New("base URL must not be empty")
+		}
+
+		o.baseURL = strings.TrimSuffix(baseURL, "/")
+
+		return nil
+	}
+}
+
+// WithAuthServerEndpoint sets the auth server used to obtain access tokens
+func WithAuthServerEndpoint(endpoint string) Option {
+	return func(o *options) error {
+		if endpoint == "" {
+			return errors.New("auth server endpoint must not be empty")
+		}
+
+		o.authConfig.AuthServerEndpoint = endpoint
+
+		return nil
+	}
+}
+
+// WithCredentials sets the client and user credentials used to authenticate against the auth server
+func WithCredentials(clientID, clientSecret, username, password string) Option {
+	return func(o *options) error {
+		o.authConfig.ClientID = clientID
+		o.authConfig.ClientSecret = clientSecret
+		o.authConfig.Username = username
+		o.authConfig.Password = password
+
+		return nil
+	}
+}
+
+// WithGrantType sets the OAuth grant type used when logging in e.g password
+func WithGrantType(grantType string) Option {
+	return func(o *options) error {
+		if grantType == "" {
+			return errors.New("grant type must not be empty")
+		}
+
+		o.authConfig.GrantType = grantType
+
+		return nil
+	}
+}
+
+// WithHTTPClient sets the http client used to make requests to health CRM
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *options) error {
+		if httpClient == nil {
+			return errors.New("http client must not be nil")
+		}
+
+		o.httpClient = httpClient
+
+		return nil
+	}
+}
+
+// WithLogger sets the logger used by the library
+func WithLogger(logger logrus.FieldLogger) Option {
+	return func(o *options) error {
+		if logger == nil {
+			return errors.New("logger must not be nil")
+		}
+
+		o.logger = logger
+
+		return nil
+	}
+}
+
+// WithTokenRefreshMargin sets how long before the access token expires it should be refreshed.
+// It defaults to one minute.
+func WithTokenRefreshMargin(margin time.Duration) Option {
+	return func(o *options) error {
+		if margin < 0 {
+			return errors.New("token refresh margin must not be negative")
+		}
+
+		o.tokenRefreshMargin = margin
+
+		return nil
+	}
+}
+
+// WithTokenRefreshJitter brings each token refresh forward by a random duration of up to jitter.
+// This spreads out refreshes from many processes sharing the same credentials. It defaults to zero.
+func WithTokenRefreshJitter(jitter time.Duration) Option {
+	return func(o *options) error {
+		if jitter < 0 {
+			return errors.New("token refresh jitter must not be negative")
+		}
+
+		o.tokenRefreshJitter = jitter
+
+		return nil
+	}
+}
+
+// WithTokenSource sets the source of the access tokens used to authorize requests.
+// When it is provided, the auth server endpoint, credentials and grant type are not used.
+// It defaults to logging in to the slade360 auth server through the authutils library.
+func WithTokenSource(tokenSource TokenSource) Option {
+	return func(o *options) error {
+		if tokenSource == nil {
+			return errors.New("token source must not be nil")
+		}
+
+		o.tokenSource = tokenSource
+
+		return nil
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried. It defaults to DefaultRetryPolicy();
+// use NoRetryPolicy() to send every request exactly once.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *options) error {
+		if err := policy.validate(); err != nil {
+			return err
+		}
+
+		o.retryPolicy = policy
+
+		return nil
+	}
+}
+
+// WithRateLimit throttles all requests made by the library, e.g to stay below health CRM's own throttling.
+// Requests wait for their turn until their context is done.
+func WithRateLimit(limit RateLimit) Option {
+	return func(o *options) error {
+		if err := limit.validate(); err != nil {
+			return err
+		}
+
+		o.rateLimit = limit
+
+		return nil
+	}
+}
+
+// WithEndpointRateLimit throttles the requests made to one group of endpoints.
+// It applies on top of the limit set with WithRateLimit.
+func WithEndpointRateLimit(group EndpointGroup, limit RateLimit) Option {
+	return func(o *options) error {
+		if !group.IsValid() {
+			return fmt.Errorf("invalid endpoint group: %s", group)
+		}
+
+		if err := limit.validate(); err != nil {
+			return err
+		}
+
+		if o.endpointRateLimits == nil {
+			o.endpointRateLimits = map[EndpointGroup]RateLimit{}
+		}
+
+		o.endpointRateLimits[group] = limit
+
+		return nil
+	}
+}
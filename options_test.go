@@ -0,0 +1,196 @@
+package healthcrm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/savannahghi/authutils"
+	"github.com/sirupsen/logrus"
+)
+
+const testAuthServerEndpoint = "https://auth.healthcrm.test"
+
+// mockTestAuthServer registers a token responder for the test auth server endpoint
+func mockTestAuthServer() {
+	httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/oauth2/token/", testAuthServerEndpoint), func(r *http.Request) (*http.Response, error) {
+		resp := authutils.OAUTHResponse{
+			ExpiresIn:    3600,
+			AccessToken:  "testAccessToken",
+			RefreshToken: "testRefreshToken",
+			TokenType:    "Bearer",
+		}
+		return httpmock.NewJsonResponse(http.StatusOK, resp)
+	})
+}
+
+// testOptions returns the options needed to build a library instance against the test auth server
+func testOptions(baseURL string, extra ...Option) []Option {
+	return append([]Option{
+		WithBaseURL(baseURL),
+		WithAuthServerEndpoint(testAuthServerEndpoint),
+		WithCredentials("client-id", "client-secret", "user@example.com", "password"),
+		WithGrantType("password"),
+	}, extra...)
+}
+
+func TestNewHealthCRMLib(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr bool
+	}{
+		{
+			name:    "Happy case: explicit options",
+			opts:    testOptions("https://staging.healthcrm.test"),
+			wantErr: false,
+		},
+		{
+			name:    "Happy case: custom http client and logger",
+			opts:    testOptions("https://staging.healthcrm.test", WithHTTPClient(&http.Client{}), WithLogger(logrus.New())),
+			wantErr: false,
+		},
+		{
+			name:    "Sad case: missing base URL",
+			opts:    []Option{WithAuthServerEndpoint(testAuthServerEndpoint)},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: empty base URL",
+			opts:    testOptions(""),
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: empty auth server endpoint",
+			opts:    []Option{WithAuthServerEndpoint("")},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: empty grant type",
+			opts:    []Option{WithGrantType("")},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: nil http client",
+			opts:    testOptions("https://staging.healthcrm.test", WithHTTPClient(nil)),
+			wantErr: true,
+		},
+		{
+			name:    "Happy case: token refresh margin and jitter",
+			opts:    testOptions("https://staging.healthcrm.test", WithTokenRefreshMargin(2*time.Minute), WithTokenRefreshJitter(30*time.Second)),
+			wantErr: false,
+		},
+		{
+			name:    "Sad case: negative token refresh margin",
+			opts:    testOptions("https://staging.healthcrm.test", WithTokenRefreshMargin(-time.Minute)),
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: negative token refresh jitter",
+			opts:    testOptions("https://staging.healthcrm.test", WithTokenRefreshJitter(-time.Minute)),
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: nil logger",
+			opts:    testOptions("https://staging.healthcrm.test", WithLogger(nil)),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			MockAuthenticate()
+			mockTestAuthServer()
+
+			_, err := NewHealthCRMLib(tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewHealthCRMLib() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+// setTestEnv sets the environment variables read by WithEnvConfig for the duration of the test
+func setTestEnv(t *testing.T) {
+	t.Helper()
+
+	t.Setenv("HEALTH_CRM_BASE_URL", baseURL)
+	t.Setenv("HEALTH_CRM_AUTH_SERVER_ENDPOINT", testAuthServerEndpoint)
+	t.Setenv("HEALTH_CRM_CLIENT_ID", "client-id")
+	t.Setenv("HEALTH_CRM_CLIENT_SECRET", "client-secret")
+	t.Setenv("HEALTH_CRM_GRANT_TYPE", "password")
+	t.Setenv("HEALTH_CRM_USERNAME", "user@example.com")
+	t.Setenv("HEALTH_CRM_PASSWORD", "password")
+}
+
+func TestWithEnvConfig(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	mockTestAuthServer()
+
+	setTestEnv(t)
+
+	h, err := NewHealthCRMLib(WithEnvConfig())
+	if err != nil {
+		t.Fatalf("NewHealthCRMLib() error = %v", err)
+	}
+	defer h.Close()
+}
+
+func TestWithEnvConfig_MissingVariable(t *testing.T) {
+	setTestEnv(t)
+	t.Setenv("HEALTH_CRM_CLIENT_SECRET", "")
+
+	_, err := NewHealthCRMLib(WithEnvConfig())
+	if err == nil {
+		t.Errorf("NewHealthCRMLib() expected an error when an environment variable is missing")
+	}
+}
+
+func TestNewHealthCRMLib_PerInstanceBaseURL(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	mockTestAuthServer()
+
+	ctx := context.Background()
+	environments := []struct {
+		baseURL  string
+		wantPath string
+	}{
+		{
+			baseURL:  "https://staging.healthcrm.test",
+			wantPath: "https://staging.healthcrm.test/v1/facilities/facilities/123/",
+		},
+		{
+			baseURL:  "https://production.healthcrm.test/",
+			wantPath: "https://production.healthcrm.test/v1/facilities/facilities/123/",
+		},
+	}
+
+	for _, environment := range environments {
+		h, err := NewHealthCRMLib(testOptions(environment.baseURL)...)
+		if err != nil {
+			t.Fatalf("unable to initialize sdk: %v", err)
+		}
+
+		calls := 0
+		httpmock.RegisterResponder(http.MethodGet, environment.wantPath, func(r *http.Request) (*http.Response, error) {
+			calls++
+			return httpmock.NewJsonResponse(http.StatusOK, FacilityOutput{ID: "123"})
+		})
+
+		_, err = h.GetFacilityByID(ctx, "123")
+		if err != nil {
+			t.Fatalf("GetFacilityByID() against %s returned error: %v", environment.baseURL, err)
+		}
+
+		if calls != 1 {
+			t.Errorf("expected one request to %s, got %d", environment.wantPath, calls)
+		}
+	}
+}
@@ -7,17 +7,7 @@ import (
 )
 
 // FacilityPage is the hospitals model used to show facility details
-type FacilityPage struct {
-	Count       int              `json:"count"`
-	Next        string           `json:"next"`
-	Previous    any              `json:"previous"`
-	PageSize    int              `json:"page_size"`
-	CurrentPage int              `json:"current_page"`
-	TotalPages  int              `json:"total_pages"`
-	StartIndex  int              `json:"start_index"`
-	EndIndex    int              `json:"end_index"`
-	Results     []FacilityOutput `json:"results"`
-}
+type FacilityPage = Page[FacilityOutput]
 
 // CoordinatesOutput is used to show geographical coordinates
 type CoordinatesOutput struct {
@@ -56,8 +46,8 @@ type FacilityOutput struct {
 	County        string                `json:"county,omitempty"`
 	Country       string                `json:"country,omitempty"`
 	Coordinates   CoordinatesOutput     `json:"coordinates,omitempty"`
-	Distance      float64               `json:"distance,omitempty"`
-	Status        string                `json:"status,omitempty"`
+	Distance      float64               `json:"distance,omitempty"` // kilometres from the location searched near, see AnnotateDistances
+	Status        FacilityStatus        `json:"status,omitempty"`
 	Address       string                `json:"address,omitempty"`
 	Contacts      []ContactsOutput      `json:"contacts,omitempty"`
 	Identifiers   []IdentifiersOutput   `json:"identifiers,omitempty"`
@@ -77,17 +67,7 @@ type BusinessHoursOutput struct {
 }
 
 // FacilityServicePage models the services offered in a facility
-type FacilityServicePage struct {
-	Results     []FacilityService `json:"results"`
-	Count       int               `json:"count"`
-	Next        string            `json:"next"`
-	Previous    string            `json:"previous"`
-	PageSize    int               `json:"page_size"`
-	CurrentPage int               `json:"current_page"`
-	TotalPages  int               `json:"total_pages"`
-	StartIndex  int               `json:"start_index"`
-	EndIndex    int               `json:"end_index"`
-}
+type FacilityServicePage = Page[FacilityService]
 
 // FacilityService models the data class that is used to show facility services
 type FacilityService struct {
@@ -205,17 +185,8 @@ type PractitionerIdentifier struct {
 	ValidTo         string                     `json:"valid_to"`
 }
 
-type Practitioners struct {
-	Count       int            `json:"count"`
-	Next        *string        `json:"next"`
-	Previous    *string        `json:"previous"`
-	PageSize    int            `json:"page_size"`
-	CurrentPage int            `json:"current_page"`
-	TotalPages  int            `json:"total_pages"`
-	StartIndex  int            `json:"start_index"`
-	EndIndex    int            `json:"end_index"`
-	Results     []Practitioner `json:"results"`
-}
+// Practitioners is a page of practitioners
+type Practitioners = Page[Practitioner]
 
 // ContactsOutput is used to show practitioners contacts
 type PractitionerContact struct {
@@ -263,17 +234,8 @@ type Practitioner struct {
 	Qualifications string                      `json:"qualifications"`
 }
 
-type Specialties struct {
-	Count       int                     `json:"count"`
-	Next        *string                 `json:"next"`
-	Previous    *string                 `json:"previous"`
-	PageSize    int                     `json:"page_size"`
-	CurrentPage int                     `json:"current_page"`
-	TotalPages  int                     `json:"total_pages"`
-	StartIndex  int                     `json:"start_index"`
-	EndIndex    int                     `json:"end_index"`
-	Results     []PractitionerSpecialty `json:"results"`
-}
+// Specialties is a page of practitioner specialties
+type Specialties = Page[PractitionerSpecialty]
 
 // FacilityImage is the photo related to a Facility in HealthCRM
 type FacilityPhoto struct {
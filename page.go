@@ -0,0 +1,111 @@
+package healthcrm
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+)
+
+// Page is a page of results from one of health CRM's paginated listings
+type Page[T any] struct {
+	Count int `json:"count"`
+	// Next is the link to the next page, or nil on the last page
+	Next *string `json:"next"`
+	// Previous is the link to the previous page, or nil on the first page
+	Previous    *string `json:"previous"`
+	PageSize    int     `json:"page_size"`
+	CurrentPage int     `json:"current_page"`
+	TotalPages  int     `json:"total_pages"`
+	StartIndex  int     `json:"start_index"`
+	EndIndex    int     `json:"end_index"`
+	Results     []T     `json:"results"`
+}
+
+// HasNext reports whether there is a page after this one
+func (p *Page[T]) HasNext() bool {
+	return p.Next != nil && *p.Next != ""
+}
+
+// HasPrevious reports whether there is a page before this one
+func (p *Page[T]) HasPrevious() bool {
+	return p.Previous != nil && *p.Previous != ""
+}
+
+// NextPagination returns the pagination that fetches the next page, or nil on the last page
+func (p *Page[T]) NextPagination() *Pagination {
+	if !p.HasNext() {
+		return nil
+	}
+
+	return pageLinkPagination(*p.Next, p.CurrentPage+1, p.PageSize)
+}
+
+// PreviousPagination returns the pagination that fetches the previous page, or nil on the first page
+func (p *Page[T]) PreviousPagination() *Pagination {
+	if !p.HasPrevious() {
+		return nil
+	}
+
+	return pageLinkPagination(*p.Previous, max(p.CurrentPage-1, 1), p.PageSize)
+}
+
+// pageLinkPagination reads the page and page size of a next or previous link.
+// Django REST framework leaves the page number out of the link to the first page, so page is used when it is missing.
+func pageLinkPagination(link string, page, pageSize int) *Pagination {
+	pagination := &Pagination{
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return pagination
+	}
+
+	query := u.Query()
+
+	if value, err := strconv.Atoi(query.Get("page")); err == nil {
+		pagination.Page = value
+	}
+
+	if value, err := strconv.Atoi(query.Get("page_size")); err == nil {
+		pagination.PageSize = value
+	}
+
+	return pagination
+}
+
+// validate checks that the page and page size are not negative
+func (p *Pagination) validate() error {
+	if p.Page < 0 {
+		return errors.New("page must not be negative")
+	}
+
+	if p.PageSize < 0 {
+		return errors.New("page size must not be negative")
+	}
+
+	return nil
+}
+
+// addTo validates the pagination and adds it to a request's query parameters.
+// A nil pagination adds nothing.
+func (p *Pagination) addTo(queryParams url.Values) error {
+	if p == nil {
+		return nil
+	}
+
+	if err := p.validate(); err != nil {
+		return err
+	}
+
+	if p.PageSize > 0 {
+		queryParams.Add("page_size", strconv.Itoa(p.PageSize))
+	}
+
+	if p.Page > 0 {
+		queryParams.Add("page", strconv.Itoa(p.Page))
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package healthcrm
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestPage_Decode(t *testing.T) {
+	body := `{
+		"count": 25,
+		"next": "https://healthcrm.test/v1/facilities/facilities/?page=3&page_size=10",
+		"previous": null,
+		"page_size": 10,
+		"current_page": 2,
+		"total_pages": 3,
+		"results": [{"id": "1"}]
+	}`
+
+	var page FacilityPage
+	if err := json.Unmarshal([]byte(body), &page); err != nil {
+		t.Fatalf("unable to decode page: %v", err)
+	}
+
+	if !page.HasNext() || page.HasPrevious() {
+		t.Errorf("Page.HasNext() = %v, Page.HasPrevious() = %v, want true and false", page.HasNext(), page.HasPrevious())
+	}
+
+	if len(page.Results) != 1 || page.Results[0].ID != "1" {
+		t.Errorf("Page.Results = %v, want the decoded facility", page.Results)
+	}
+}
+
+func TestPage_NextPagination(t *testing.T) {
+	link := func(s string) *string { return &s }
+
+	tests := []struct {
+		name         string
+		page         Page[int]
+		wantNext     *Pagination
+		wantPrevious *Pagination
+	}{
+		{
+			name: "Happy case: middle page",
+			page: Page[int]{
+				Next:        link("https://healthcrm.test/v1/facilities/services/?page=3&page_size=10"),
+				Previous:    link("https://healthcrm.test/v1/facilities/services/?page=1&page_size=10"),
+				CurrentPage: 2,
+				PageSize:    10,
+			},
+			wantNext:     &Pagination{Page: 3, PageSize: 10},
+			wantPrevious: &Pagination{Page: 1, PageSize: 10},
+		},
+		{
+			name: "Happy case: link to the first page without a page number",
+			page: Page[int]{
+				Next:        link("https://healthcrm.test/v1/facilities/services/?page=3"),
+				Previous:    link("https://healthcrm.test/v1/facilities/services/"),
+				CurrentPage: 2,
+				PageSize:    20,
+			},
+			wantNext:     &Pagination{Page: 3, PageSize: 20},
+			wantPrevious: &Pagination{Page: 1, PageSize: 20},
+		},
+		{
+			name: "Happy case: only page",
+			page: Page[int]{
+				CurrentPage: 1,
+				PageSize:    20,
+			},
+		},
+		{
+			name: "Happy case: empty links",
+			page: Page[int]{
+				Next:     link(""),
+				Previous: link(""),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.page.NextPagination(); !reflect.DeepEqual(got, tt.wantNext) {
+				t.Errorf("Page.NextPagination() = %v, want %v", got, tt.wantNext)
+			}
+
+			if got := tt.page.PreviousPagination(); !reflect.DeepEqual(got, tt.wantPrevious) {
+				t.Errorf("Page.PreviousPagination() = %v, want %v", got, tt.wantPrevious)
+			}
+		})
+	}
+}
+
+func TestPagination_addTo(t *testing.T) {
+	tests := []struct {
+		name       string
+		pagination *Pagination
+		want       url.Values
+		wantErr    bool
+	}{
+		{
+			name:       "Happy case: page and page size",
+			pagination: &Pagination{Page: 2, PageSize: 10},
+			want:       url.Values{"page": {"2"}, "page_size": {"10"}},
+		},
+		{
+			name:       "Happy case: zero values are left out",
+			pagination: &Pagination{},
+			want:       url.Values{},
+		},
+		{
+			name:       "Happy case: nil pagination",
+			pagination: nil,
+			want:       url.Values{},
+		},
+		{
+			name:       "Sad case: negative page",
+			pagination: &Pagination{Page: -1},
+			wantErr:    true,
+		},
+		{
+			name:       "Sad case: negative page size",
+			pagination: &Pagination{PageSize: -10},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := url.Values{}
+
+			err := tt.pagination.addTo(got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Pagination.addTo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Pagination.addTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHealthCRMLib_InvalidPagination(t *testing.T) {
+	h := &HealthCRMLib{}
+	ctx := context.Background()
+	pagination := &Pagination{Page: -1}
+
+	if _, err := h.GetServices(ctx, pagination, "05"); err == nil {
+		t.Errorf("HealthCRMLib.GetServices() expected an error for a negative page")
+	}
+
+	if _, err := h.GetSpecialties(ctx, pagination, "05"); err == nil {
+		t.Errorf("HealthCRMLib.GetSpecialties() expected an error for a negative page")
+	}
+
+	if _, err := h.GetFacilitiesOfferingAService(ctx, "123", pagination); err == nil {
+		t.Errorf("HealthCRMLib.GetFacilitiesOfferingAService() expected an error for a negative page")
+	}
+
+	if _, err := h.GetFacilities(ctx, FilterFacilitiesInput{CrmServiceCode: "05", Pagination: pagination}); err == nil {
+		t.Errorf("HealthCRMLib.GetFacilities() expected an error for a negative page")
+	}
+
+	if _, err := h.GetPractitioners(ctx, FilterPractitionersInput{CrmServiceCode: "05", Pagination: pagination}); err == nil {
+		t.Errorf("HealthCRMLib.GetPractitioners() expected an error for a negative page")
+	}
+}
@@ -0,0 +1,229 @@
+package healthcrm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+)
+
+// MaxFacilityPhotoSize is the largest photo, in bytes, that can be uploaded
+const MaxFacilityPhotoSize = 5 << 20
+
+var (
+	// ErrPhotoTooLarge is returned when a photo is larger than MaxFacilityPhotoSize
+	ErrPhotoTooLarge = fmt.Errorf("facility photo must not be larger than %d bytes", MaxFacilityPhotoSize)
+
+	// ErrUnsupportedPhotoType is returned when a photo is not a JPEG, PNG, GIF or WebP image
+	ErrUnsupportedPhotoType = errors.New("facility photo must be a JPEG, PNG, GIF or WebP image")
+)
+
+// photoExtensions maps the image types that can be uploaded to the extension of their file names
+var photoExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// FacilityPhotoInput describes a photo being uploaded
+type FacilityPhotoInput struct {
+	Title       string
+	Description string
+	// FileName is the name the photo is stored under. It defaults to the title with an extension for the photo's type.
+	FileName string
+}
+
+// facilityPhotosPath returns the path of a facility's photos
+func facilityPhotosPath(facilityID string) string {
+	return fmt.Sprintf("/v1/facilities/facilities/%s/facility_images/", facilityID)
+}
+
+// readPhoto reads a photo of at most MaxFacilityPhotoSize bytes and sniffs its content type
+func readPhoto(photo io.Reader) ([]byte, string, error) {
+	data, err := io.ReadAll(io.LimitReader(photo, MaxFacilityPhotoSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("could not read photo: %w", err)
+	}
+
+	if len(data) == 0 {
+		return nil, "", errors.New("facility photo must not be empty")
+	}
+
+	if len(data) > MaxFacilityPhotoSize {
+		return nil, "", ErrPhotoTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	if _, ok := photoExtensions[contentType]; !ok {
+		return nil, "", fmt.Errorf("%w: got %s", ErrUnsupportedPhotoType, contentType)
+	}
+
+	return data, contentType, nil
+}
+
+// encodePhotoForm writes the photo and its details as multipart/form-data.
+// It returns the encoded form and its content type.
+func encodePhotoForm(data []byte, contentType string, meta FacilityPhotoInput) ([]byte, string, error) {
+	fileName := meta.FileName
+	if fileName == "" {
+		fileName = strings.ReplaceAll(strings.ToLower(meta.Title), " ", "-") + photoExtensions[contentType]
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	if err := writer.WriteField("title", meta.Title); err != nil {
+		return nil, "", err
+	}
+
+	if meta.Description != "" {
+		if err := writer.WriteField("description", meta.Description); err != nil {
+			return nil, "", err
+		}
+	}
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="document"; filename=%q`, fileName))
+	header.Set("Content-Type", contentType)
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if _, err := part.Write(data); err != nil {
+		return nil, "", err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return body.Bytes(), writer.FormDataContentType(), nil
+}
+
+// UploadFacilityPhoto uploads a photo of a facility.
+// The photo must be a JPEG, PNG, GIF or WebP image of at most MaxFacilityPhotoSize bytes; its type is detected from its content.
+func (h *HealthCRMLib) UploadFacilityPhoto(ctx context.Context, facilityID string, photo io.Reader, meta FacilityPhotoInput) (*FacilityPhoto, error) {
+	if facilityID == "" {
+		return nil, errors.New("facility ID must be provided")
+	}
+
+	if meta.Title == "" {
+		return nil, errors.New("photo title must be provided")
+	}
+
+	data, contentType, err := readPhoto(photo)
+	if err != nil {
+		return nil, err
+	}
+
+	body, formContentType, err := encodePhotoForm(data, contentType, meta)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode photo: %w", err)
+	}
+
+	response, err := h.client.MakeMultipartRequest(ctx, http.MethodPost, facilityPhotosPath(facilityID), body, formContentType)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	respBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read response: %w", err)
+	}
+
+	if response.StatusCode != http.StatusCreated {
+		return nil, newResponseError(response, respBytes)
+	}
+
+	var output *FacilityPhoto
+
+	err = json.Unmarshal(respBytes, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// ListFacilityPhotos fetches all the photos of a facility, following every page of the listing
+func (h *HealthCRMLib) ListFacilityPhotos(ctx context.Context, facilityID string) ([]FacilityPhoto, error) {
+	if facilityID == "" {
+		return nil, errors.New("facility ID must be provided")
+	}
+
+	return collect(paginate(ctx, func(ctx context.Context, pagination *Pagination) (*Page[FacilityPhoto], error) {
+		return h.getFacilityPhotos(ctx, facilityID, pagination)
+	}, nil))
+}
+
+// getFacilityPhotos fetches one page of a facility's photos
+func (h *HealthCRMLib) getFacilityPhotos(ctx context.Context, facilityID string, pagination *Pagination) (*Page[FacilityPhoto], error) {
+	queryParams := url.Values{}
+	if err := pagination.addTo(queryParams); err != nil {
+		return nil, err
+	}
+
+	response, err := h.client.MakeRequest(ctx, http.MethodGet, facilityPhotosPath(facilityID), queryParams, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	respBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read response: %w", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, newResponseError(response, respBytes)
+	}
+
+	var photos *Page[FacilityPhoto]
+
+	err = json.Unmarshal(respBytes, &photos)
+	if err != nil {
+		return nil, err
+	}
+
+	return photos, nil
+}
+
+// DeleteFacilityPhoto removes one of a facility's photos
+func (h *HealthCRMLib) DeleteFacilityPhoto(ctx context.Context, facilityID, photoID string) error {
+	if facilityID == "" || photoID == "" {
+		return errors.New("facility ID and photo ID must be provided")
+	}
+
+	path := fmt.Sprintf("%s%s/", facilityPhotosPath(facilityID), photoID)
+
+	response, err := h.client.MakeRequest(ctx, http.MethodDelete, path, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	respBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("could not read response: %w", err)
+	}
+
+	if response.StatusCode != http.StatusNoContent && response.StatusCode != http.StatusOK {
+		return newResponseError(response, respBytes)
+	}
+
+	return nil
+}
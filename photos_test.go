@@ -0,0 +1,259 @@
+package healthcrm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// testPNG is the start of a PNG image, which is enough for its content type to be detected
+var testPNG = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 64)...)
+
+func TestReadPhoto(t *testing.T) {
+	tests := []struct {
+		name            string
+		photo           io.Reader
+		wantContentType string
+		wantErr         error
+	}{
+		{
+			name:            "Happy case: png",
+			photo:           bytes.NewReader(testPNG),
+			wantContentType: "image/png",
+		},
+		{
+			name:            "Happy case: jpeg",
+			photo:           bytes.NewReader(append([]byte("\xff\xd8\xff\xe0"), bytes.Repeat([]byte{0}, 64)...)),
+			wantContentType: "image/jpeg",
+		},
+		{
+			name:            "Happy case: largest allowed photo",
+			photo:           io.MultiReader(bytes.NewReader(testPNG), bytes.NewReader(make([]byte, MaxFacilityPhotoSize-len(testPNG)))),
+			wantContentType: "image/png",
+		},
+		{
+			name:    "Sad case: too large",
+			photo:   io.MultiReader(bytes.NewReader(testPNG), bytes.NewReader(make([]byte, MaxFacilityPhotoSize))),
+			wantErr: ErrPhotoTooLarge,
+		},
+		{
+			name:    "Sad case: not an image",
+			photo:   strings.NewReader("%PDF-1.7 facility license"),
+			wantErr: ErrUnsupportedPhotoType,
+		},
+		{
+			name:    "Sad case: empty",
+			photo:   strings.NewReader(""),
+			wantErr: errors.New("facility photo must not be empty"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, contentType, err := readPhoto(tt.photo)
+			if tt.wantErr != nil {
+				if err == nil || (!errors.Is(err, tt.wantErr) && err.Error() != tt.wantErr.Error()) {
+					t.Errorf("readPhoto() error = %v, want %v", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("readPhoto() error = %v", err)
+			}
+
+			if contentType != tt.wantContentType {
+				t.Errorf("readPhoto() content type = %s, want %s", contentType, tt.wantContentType)
+			}
+		})
+	}
+}
+
+func TestHealthCRMLib_UploadFacilityPhoto(t *testing.T) {
+	tests := []struct {
+		name         string
+		meta         FacilityPhotoInput
+		photo        io.Reader
+		statusCode   int
+		wantFileName string
+		wantErr      bool
+	}{
+		{
+			name:         "Happy case: upload photo",
+			meta:         FacilityPhotoInput{Title: "Main Entrance", Description: "The entrance from the main road"},
+			photo:        bytes.NewReader(testPNG),
+			statusCode:   http.StatusCreated,
+			wantFileName: "main-entrance.png",
+		},
+		{
+			name:         "Happy case: upload photo with a file name",
+			meta:         FacilityPhotoInput{Title: "Main Entrance", FileName: "IMG_0001.png"},
+			photo:        bytes.NewReader(testPNG),
+			statusCode:   http.StatusCreated,
+			wantFileName: "IMG_0001.png",
+		},
+		{
+			name:       "Sad case: upload rejected",
+			meta:       FacilityPhotoInput{Title: "Main Entrance"},
+			photo:      bytes.NewReader(testPNG),
+			statusCode: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:    "Sad case: missing title",
+			photo:   bytes.NewReader(testPNG),
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: not an image",
+			meta:    FacilityPhotoInput{Title: "License"},
+			photo:   strings.NewReader("%PDF-1.7 facility license"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			MockAuthenticate()
+
+			var (
+				title, description, fileName, partContentType string
+				file                                          []byte
+			)
+
+			path := fmt.Sprintf("%s/v1/facilities/facilities/123/facility_images/", baseURL)
+			httpmock.RegisterResponder(http.MethodPost, path, func(r *http.Request) (*http.Response, error) {
+				if err := r.ParseMultipartForm(MaxFacilityPhotoSize); err != nil {
+					return nil, err
+				}
+
+				title, description = r.FormValue("title"), r.FormValue("description")
+
+				document, header, err := r.FormFile("document")
+				if err != nil {
+					return nil, err
+				}
+				defer document.Close()
+
+				fileName, partContentType = header.Filename, header.Header.Get("Content-Type")
+
+				if file, err = io.ReadAll(document); err != nil {
+					return nil, err
+				}
+
+				if tt.statusCode != http.StatusCreated {
+					return httpmock.NewStringResponse(tt.statusCode, `{"document": ["Upload a valid image."]}`), nil
+				}
+
+				return httpmock.NewJsonResponse(http.StatusCreated, &FacilityPhoto{
+					ID:          "456",
+					Title:       title,
+					ImageURL:    "https://storage.example.com/" + fileName,
+					Size:        int64(len(file)),
+					ContentType: partContentType,
+					Facility:    "123",
+				})
+			})
+
+			h, err := NewHealthCRMLib(testOptions(baseURL)...)
+			if err != nil {
+				t.Fatalf("unable to initialize sdk: %v", err)
+			}
+			defer h.Close()
+
+			photo, err := h.UploadFacilityPhoto(context.Background(), "123", tt.photo, tt.meta)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HealthCRMLib.UploadFacilityPhoto() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.statusCode == 0 {
+				if count := httpmock.GetCallCountInfo()["POST "+path]; count != 0 {
+					t.Errorf("invalid photo sent %d requests, want none", count)
+				}
+
+				return
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if title != tt.meta.Title || description != tt.meta.Description || fileName != tt.wantFileName {
+				t.Errorf("uploaded title = %q, description = %q, file name = %q", title, description, fileName)
+			}
+
+			if partContentType != "image/png" || !bytes.Equal(file, testPNG) {
+				t.Errorf("uploaded %d bytes as %s, want the PNG", len(file), partContentType)
+			}
+
+			if photo.ID != "456" || photo.Size != int64(len(testPNG)) {
+				t.Errorf("HealthCRMLib.UploadFacilityPhoto() = %+v, want the uploaded photo", photo)
+			}
+		})
+	}
+}
+
+func TestHealthCRMLib_FacilityPhotos(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	MockAuthenticate()
+
+	listPath := fmt.Sprintf("%s/v1/facilities/facilities/123/facility_images/", baseURL)
+	httpmock.RegisterResponder(http.MethodGet, listPath, func(r *http.Request) (*http.Response, error) {
+		if r.URL.Query().Get("page") == "2" {
+			return httpmock.NewStringResponse(http.StatusOK, `{
+				"count": 2,
+				"next": null,
+				"previous": "page=1",
+				"results": [{"id": "789", "title": "Maternity Wing", "document": "https://storage.example.com/maternity-wing.png", "size": 72, "content_type": "image/png", "facility_id": "123"}]
+			}`), nil
+		}
+
+		return httpmock.NewStringResponse(http.StatusOK, `{
+			"count": 2,
+			"next": "page=2",
+			"previous": null,
+			"results": [{"id": "456", "title": "Main Entrance", "document": "https://storage.example.com/main-entrance.png", "size": 72, "content_type": "image/png", "facility_id": "123"}]
+		}`), nil
+	})
+
+	deletePath := fmt.Sprintf("%s/v1/facilities/facilities/123/facility_images/456/", baseURL)
+	httpmock.RegisterResponder(http.MethodDelete, deletePath, httpmock.NewStringResponder(http.StatusNoContent, ""))
+
+	h, err := NewHealthCRMLib(testOptions(baseURL)...)
+	if err != nil {
+		t.Fatalf("unable to initialize sdk: %v", err)
+	}
+	defer h.Close()
+
+	photos, err := h.ListFacilityPhotos(context.Background(), "123")
+	if err != nil {
+		t.Fatalf("HealthCRMLib.ListFacilityPhotos() error = %v", err)
+	}
+
+	if len(photos) != 2 || photos[0].ImageURL != "https://storage.example.com/main-entrance.png" || photos[1].ID != "789" {
+		t.Errorf("HealthCRMLib.ListFacilityPhotos() = %+v, want the photos on both pages", photos)
+	}
+
+	if err := h.DeleteFacilityPhoto(context.Background(), "123", "456"); err != nil {
+		t.Errorf("HealthCRMLib.DeleteFacilityPhoto() error = %v", err)
+	}
+
+	httpmock.RegisterResponder(http.MethodDelete, deletePath, httpmock.NewStringResponder(http.StatusNotFound, `{"detail": "Not found."}`))
+
+	if err := h.DeleteFacilityPhoto(context.Background(), "123", "456"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("HealthCRMLib.DeleteFacilityPhoto() error = %v, want %v", err, ErrNotFound)
+	}
+
+	if err := h.DeleteFacilityPhoto(context.Background(), "123", ""); err == nil {
+		t.Errorf("HealthCRMLib.DeleteFacilityPhoto() expected an error for a missing photo ID")
+	}
+}
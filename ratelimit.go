@@ -0,0 +1,314 @@
+package healthcrm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EndpointGroup identifies a group of health CRM endpoints that can be given their own rate limit
+type EndpointGroup string
+
+const (
+	EndpointGroupFacilities    EndpointGroup = "facilities"
+	EndpointGroupIdentities    EndpointGroup = "identities"
+	EndpointGroupPractitioners EndpointGroup = "practitioners"
+)
+
+// IsValid returns true if an endpoint group is valid
+func (e EndpointGroup) IsValid() bool {
+	switch e {
+	case EndpointGroupFacilities, EndpointGroupIdentities, EndpointGroupPractitioners:
+		return true
+	default:
+		return false
+	}
+}
+
+// String converts the endpoint group to a string
+func (e EndpointGroup) String() string {
+	return string(e)
+}
+
+// endpointGroupOf returns the endpoint group that a request path such as /v1/facilities/facilities/ belongs to
+func endpointGroupOf(path string) EndpointGroup {
+	segments := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 3)
+	if len(segments) < 2 {
+		return ""
+	}
+
+	return EndpointGroup(segments[1])
+}
+
+// RateLimit configures client side throttling of requests to health CRM
+type RateLimit struct {
+	// RequestsPerSecond is the rate at which requests may be sent. Zero means no rate limit.
+	RequestsPerSecond float64
+	// Burst is the number of requests that may be sent at once before the rate applies. It defaults to 1.
+	Burst int
+	// MaxInFlight is the maximum number of requests waiting for a response at any time. Zero means no limit.
+	MaxInFlight int
+}
+
+// validate checks that the rate limit's values make sense
+func (r RateLimit) validate() error {
+	if r.RequestsPerSecond < 0 || r.Burst < 0 || r.MaxInFlight < 0 {
+		return errors.New("rate limit values must not be negative")
+	}
+
+	return nil
+}
+
+// RateLimitStats reports how requests have been held back by the client side rate limits
+type RateLimitStats struct {
+	// Requests is the number of requests that went through the rate limiter
+	Requests int64
+	// Delayed is the number of requests that had to wait before being sent
+	Delayed int64
+	// WaitTime is the total time requests spent waiting
+	WaitTime time.Duration
+}
+
+// tokenBucket is a token bucket rate limiter
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket creates a full bucket that refills at rate tokens per second
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// reserve takes a token and returns how long to wait before it may be used
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel returns a reserved token that was not used
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = min(b.burst, b.tokens+1)
+}
+
+// wait blocks until a token is available or ctx is done
+func (b *tokenBucket) wait(ctx context.Context) error {
+	delay := b.reserve(time.Now())
+	if delay == 0 {
+		return nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		b.cancel()
+		return context.DeadlineExceeded
+	}
+
+	if err := sleep(ctx, delay); err != nil {
+		b.cancel()
+		return err
+	}
+
+	return nil
+}
+
+// limiter applies a RateLimit
+type limiter struct {
+	bucket   *tokenBucket
+	inFlight chan struct{}
+}
+
+// newLimiter returns a limiter for limit, or nil if limit does not restrict anything
+func newLimiter(limit RateLimit) *limiter {
+	l := &limiter{}
+
+	if limit.RequestsPerSecond > 0 {
+		l.bucket = newTokenBucket(limit.RequestsPerSecond, limit.Burst)
+	}
+
+	if limit.MaxInFlight > 0 {
+		l.inFlight = make(chan struct{}, limit.MaxInFlight)
+	}
+
+	if l.bucket == nil && l.inFlight == nil {
+		return nil
+	}
+
+	return l
+}
+
+// acquire waits for a free slot and a token. The returned function releases the slot.
+func (l *limiter) acquire(ctx context.Context) (func(), error) {
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	release := func() {
+		if l.inFlight != nil {
+			<-l.inFlight
+		}
+	}
+
+	if l.bucket != nil {
+		if err := l.bucket.wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	return release, nil
+}
+
+// rateLimiter holds the client wide and per endpoint group limiters
+type rateLimiter struct {
+	global *limiter
+	groups map[EndpointGroup]*limiter
+
+	requests atomic.Int64
+	delayed  atomic.Int64
+	waitTime atomic.Int64
+}
+
+// newRateLimiter returns a rateLimiter, or nil if no limits are configured
+func newRateLimiter(global RateLimit, groups map[EndpointGroup]RateLimit) *rateLimiter {
+	r := &rateLimiter{
+		global: newLimiter(global),
+		groups: map[EndpointGroup]*limiter{},
+	}
+
+	for group, limit := range groups {
+		if l := newLimiter(limit); l != nil {
+			r.groups[group] = l
+		}
+	}
+
+	if r.global == nil && len(r.groups) == 0 {
+		return nil
+	}
+
+	return r
+}
+
+// acquire waits until a request to path may be sent. The returned function must be called once the request is done.
+func (r *rateLimiter) acquire(ctx context.Context, path string) (func(), error) {
+	var releases []func()
+	release := func() {
+		for i := len(releases) - 1; i >= 0; i-- {
+			releases[i]()
+		}
+	}
+
+	start := time.Now()
+
+	// the group comes first so that a request held back by its group does not hold a client wide slot while it waits
+	for _, l := range []*limiter{r.groups[endpointGroupOf(path)], r.global} {
+		if l == nil {
+			continue
+		}
+
+		done, err := l.acquire(ctx)
+		if err != nil {
+			release()
+			return nil, err
+		}
+
+		releases = append(releases, done)
+	}
+
+	r.requests.Add(1)
+
+	// waits shorter than this are just the cost of taking the locks
+	if waited := time.Since(start); waited > time.Millisecond {
+		r.delayed.Add(1)
+		r.waitTime.Add(int64(waited))
+	}
+
+	return release, nil
+}
+
+// stats returns how requests have been held back so far
+func (r *rateLimiter) stats() RateLimitStats {
+	if r == nil {
+		return RateLimitStats{}
+	}
+
+	return RateLimitStats{
+		Requests: r.requests.Load(),
+		Delayed:  r.delayed.Load(),
+		WaitTime: time.Duration(r.waitTime.Load()),
+	}
+}
+
+// releaseOnClose calls release once the response body is closed
+type releaseOnClose struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+// Close closes the body and releases the rate limiter slot
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(r.release)
+
+	return err
+}
+
+// sendLimited sends request once the rate limiter allows it.
+// The in-flight slot is held until the response body is closed.
+func (c *client) sendLimited(ctx context.Context, request *http.Request, path string) (*http.Response, error) {
+	if c.rateLimiter == nil {
+		return c.httpClient.Do(request)
+	}
+
+	release, err := c.rateLimiter.acquire(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	response.Body = &releaseOnClose{
+		ReadCloser: response.Body,
+		release:    release,
+	}
+
+	return response, nil
+}
@@ -0,0 +1,226 @@
+package healthcrm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestEndpointGroupOf(t *testing.T) {
+	tests := []struct {
+		path string
+		want EndpointGroup
+	}{
+		{path: "/v1/facilities/facilities/", want: EndpointGroupFacilities},
+		{path: "/v1/facilities/services?service_ids=1,2", want: EndpointGroupFacilities},
+		{path: "/v1/identities/profiles/match_profile/", want: EndpointGroupIdentities},
+		{path: "/v1/practitioners/specialties/", want: EndpointGroupPractitioners},
+		{path: "/", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := endpointGroupOf(tt.path); got != tt.want {
+				t.Errorf("endpointGroupOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// registerOKResponders responds with 200 OK to the facilities and identities endpoints used in the rate limit tests
+func registerOKResponders() {
+	httpmock.RegisterResponder(http.MethodGet, "https://healthcrm.test/v1/facilities/facilities/", httpmock.NewStringResponder(http.StatusOK, "{}"))
+	httpmock.RegisterResponder(http.MethodGet, "https://healthcrm.test/v1/identities/profiles/", httpmock.NewStringResponder(http.StatusOK, "{}"))
+}
+
+func TestMakeRequest_RateLimit(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerOKResponders()
+
+	mockClient := newTestClient(t, &MockAuthUtilsLib{})
+	mockClient.rateLimiter = newRateLimiter(RateLimit{RequestsPerSecond: 20, Burst: 1}, nil)
+
+	start := time.Now()
+
+	for i := 0; i < 5; i++ {
+		response, err := mockClient.MakeRequest(context.Background(), http.MethodGet, "/v1/facilities/facilities/", nil, nil)
+		if err != nil {
+			t.Fatalf("Error making request: %v", err)
+		}
+
+		response.Body.Close()
+	}
+
+	// the first request uses the burst, the other four wait 50ms each
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("Expected the requests to be throttled, took %v", elapsed)
+	}
+
+	stats := mockClient.rateLimiter.stats()
+	if stats.Requests != 5 || stats.Delayed == 0 || stats.WaitTime == 0 {
+		t.Errorf("Expected the waits to be counted, got %+v", stats)
+	}
+}
+
+func TestMakeRequest_RateLimitRespectsContext(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerOKResponders()
+
+	mockClient := newTestClient(t, &MockAuthUtilsLib{})
+	mockClient.rateLimiter = newRateLimiter(RateLimit{RequestsPerSecond: 0.1, Burst: 1}, nil)
+
+	response, err := mockClient.MakeRequest(context.Background(), http.MethodGet, "/v1/facilities/facilities/", nil, nil)
+	if err != nil {
+		t.Fatalf("Error making request: %v", err)
+	}
+
+	response.Body.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = mockClient.MakeRequest(ctx, http.MethodGet, "/v1/facilities/facilities/", nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected %v while waiting for the rate limit, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestMakeRequest_MaxInFlight(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerOKResponders()
+
+	mockClient := newTestClient(t, &MockAuthUtilsLib{})
+	mockClient.rateLimiter = newRateLimiter(RateLimit{}, map[EndpointGroup]RateLimit{
+		EndpointGroupFacilities: {MaxInFlight: 1},
+	})
+
+	held, err := mockClient.MakeRequest(context.Background(), http.MethodGet, "/v1/facilities/facilities/", nil, nil)
+	if err != nil {
+		t.Fatalf("Error making request: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = mockClient.MakeRequest(ctx, http.MethodGet, "/v1/facilities/facilities/", nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the second facilities request to wait for the first, got %v", err)
+	}
+
+	response, err := mockClient.MakeRequest(context.Background(), http.MethodGet, "/v1/identities/profiles/", nil, nil)
+	if err != nil {
+		t.Fatalf("Expected identities requests not to be limited, got %v", err)
+	}
+
+	response.Body.Close()
+	held.Body.Close()
+
+	response, err = mockClient.MakeRequest(context.Background(), http.MethodGet, "/v1/facilities/facilities/", nil, nil)
+	if err != nil {
+		t.Fatalf("Expected the slot to be released once the body is closed, got %v", err)
+	}
+
+	response.Body.Close()
+}
+
+func TestMakeRequest_GroupLimitDoesNotHoldGlobalSlots(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerOKResponders()
+
+	mockClient := newTestClient(t, &MockAuthUtilsLib{})
+	mockClient.rateLimiter = newRateLimiter(RateLimit{MaxInFlight: 2}, map[EndpointGroup]RateLimit{
+		EndpointGroupFacilities: {MaxInFlight: 1},
+	})
+
+	held, err := mockClient.MakeRequest(context.Background(), http.MethodGet, "/v1/facilities/facilities/", nil, nil)
+	if err != nil {
+		t.Fatalf("Error making request: %v", err)
+	}
+	defer held.Body.Close()
+
+	// a second facilities request waits for the facilities slot
+	waitCtx, cancelWait := context.WithCancel(context.Background())
+	waiting := make(chan error, 1)
+
+	go func() {
+		_, err := mockClient.MakeRequest(waitCtx, http.MethodGet, "/v1/facilities/facilities/", nil, nil)
+		waiting <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	response, err := mockClient.MakeRequest(ctx, http.MethodGet, "/v1/identities/profiles/", nil, nil)
+	if err != nil {
+		t.Errorf("Expected the identities request to get the free client wide slot, got %v", err)
+	} else {
+		response.Body.Close()
+	}
+
+	cancelWait()
+
+	if err := <-waiting; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the waiting facilities request to be cancelled, got %v", err)
+	}
+}
+
+func TestNewRateLimiter_Unlimited(t *testing.T) {
+	if r := newRateLimiter(RateLimit{}, map[EndpointGroup]RateLimit{EndpointGroupFacilities: {}}); r != nil {
+		t.Errorf("newRateLimiter() expected no limiter when no limits are set")
+	}
+
+	if stats := (*rateLimiter)(nil).stats(); stats != (RateLimitStats{}) {
+		t.Errorf("stats() = %+v, want zero stats without a limiter", stats)
+	}
+}
+
+func TestWithEndpointRateLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		opt     Option
+		wantErr bool
+	}{
+		{
+			name:    "Happy case: client wide limit",
+			opt:     WithRateLimit(RateLimit{RequestsPerSecond: 10, Burst: 5, MaxInFlight: 4}),
+			wantErr: false,
+		},
+		{
+			name:    "Happy case: endpoint group limit",
+			opt:     WithEndpointRateLimit(EndpointGroupPractitioners, RateLimit{RequestsPerSecond: 2}),
+			wantErr: false,
+		},
+		{
+			name:    "Sad case: negative limit",
+			opt:     WithRateLimit(RateLimit{RequestsPerSecond: -1}),
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: invalid endpoint group",
+			opt:     WithEndpointRateLimit(EndpointGroup("billing"), RateLimit{RequestsPerSecond: 2}),
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: negative endpoint group limit",
+			opt:     WithEndpointRateLimit(EndpointGroupIdentities, RateLimit{MaxInFlight: -1}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opt(defaultOptions())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("option error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
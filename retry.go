@@ -0,0 +1,168 @@
+package healthcrm
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	// idempotencyKeyHeader carries the caller's idempotency key to health CRM
+	idempotencyKeyHeader = "Idempotency-Key"
+
+	defaultRetryMaxAttempts    = 3
+	defaultRetryInitialBackoff = 100 * time.Millisecond
+	defaultRetryMaxBackoff     = 2 * time.Second
+)
+
+// RetryPolicy controls how failed requests to health CRM are retried
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made for a request, including the first one.
+	// A value of 1 or less disables retries.
+	MaxAttempts int
+
+	// Methods lists the HTTP methods that are always retried. Other methods (POST and PATCH)
+	// are only retried when the request carries an idempotency key (see WithIdempotencyKey).
+	Methods []string
+
+	// StatusCodes lists the response status codes that are retried
+	StatusCodes []int
+
+	// InitialBackoff is the wait before the first retry. It doubles on every retry up to MaxBackoff,
+	// and a random jitter of up to half the wait is applied.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// RespectRetryAfter waits for the duration in the response's Retry-After header when it is longer than the backoff
+	RespectRetryAfter bool
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured.
+// GET requests are retried up to three times on 502, 503 and 504 responses and on connection errors.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: defaultRetryMaxAttempts,
+		Methods:     []string{http.MethodGet},
+		StatusCodes: []int{
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		InitialBackoff:    defaultRetryInitialBackoff,
+		MaxBackoff:        defaultRetryMaxBackoff,
+		RespectRetryAfter: true,
+	}
+}
+
+// NoRetryPolicy returns a retry policy that sends every request exactly once
+func NoRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+// validate checks that the retry policy's values make sense
+func (p RetryPolicy) validate() error {
+	if p.InitialBackoff < 0 || p.MaxBackoff < 0 {
+		return errors.New("retry backoff must not be negative")
+	}
+
+	if p.MaxBackoff < p.InitialBackoff {
+		return errors.New("maximum retry backoff must not be less than the initial backoff")
+	}
+
+	return nil
+}
+
+// retryable reports whether a request with the given method may be retried
+func (p RetryPolicy) retryable(method string, hasIdempotencyKey bool) bool {
+	if p.MaxAttempts <= 1 {
+		return false
+	}
+
+	if slices.Contains(p.Methods, method) {
+		return true
+	}
+
+	return hasIdempotencyKey && (method == http.MethodPost || method == http.MethodPatch)
+}
+
+// retryableStatus reports whether a response with the given status code should be retried
+func (p RetryPolicy) retryableStatus(statusCode int) bool {
+	return slices.Contains(p.StatusCodes, statusCode)
+}
+
+// backoff returns how long to wait before the given retry, starting from 1
+func (p RetryPolicy) backoff(retry int, response *http.Response) time.Duration {
+	wait := p.InitialBackoff
+	for i := 1; i < retry && wait < p.MaxBackoff; i++ {
+		wait *= 2
+	}
+
+	wait = min(wait, p.MaxBackoff)
+
+	if wait > 1 {
+		half := wait / 2
+		wait = half + rand.N(wait-half) //nolint:gosec
+	}
+
+	if p.RespectRetryAfter && response != nil {
+		if retryAfter, ok := parseRetryAfter(response.Header.Get("Retry-After"), time.Now()); ok && retryAfter > wait {
+			wait = retryAfter
+		}
+	}
+
+	return wait
+}
+
+// parseRetryAfter reads a Retry-After header given either in seconds or as a HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(date.Sub(now), 0), true
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a context that sends key as the Idempotency-Key header on requests made with it.
+// POST and PATCH requests made with such a context may be retried by the retry policy.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// idempotencyKeyFromContext returns the idempotency key set with WithIdempotencyKey
+func idempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+
+	return key
+}
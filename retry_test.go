@@ -0,0 +1,357 @@
+package healthcrm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// fastRetryPolicy retries quickly so that tests do not wait on the backoff
+func fastRetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+
+	return policy
+}
+
+func TestMakeRequest_Retry(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		idempotencyKey string
+		policy         RetryPolicy
+		responses      []int
+		wantStatus     int
+		wantCalls      int
+	}{
+		{
+			name:       "Happy case: GET retried after 503",
+			method:     http.MethodGet,
+			policy:     fastRetryPolicy(),
+			responses:  []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			wantStatus: http.StatusOK,
+			wantCalls:  3,
+		},
+		{
+			name:       "Sad case: GET gives up after max attempts",
+			method:     http.MethodGet,
+			policy:     fastRetryPolicy(),
+			responses:  []int{http.StatusGatewayTimeout, http.StatusGatewayTimeout, http.StatusGatewayTimeout, http.StatusOK},
+			wantStatus: http.StatusGatewayTimeout,
+			wantCalls:  3,
+		},
+		{
+			name:       "Happy case: non retryable status is returned straight away",
+			method:     http.MethodGet,
+			policy:     fastRetryPolicy(),
+			responses:  []int{http.StatusNotFound, http.StatusOK},
+			wantStatus: http.StatusNotFound,
+			wantCalls:  1,
+		},
+		{
+			name:       "Happy case: POST without an idempotency key is not retried",
+			method:     http.MethodPost,
+			policy:     fastRetryPolicy(),
+			responses:  []int{http.StatusServiceUnavailable, http.StatusCreated},
+			wantStatus: http.StatusServiceUnavailable,
+			wantCalls:  1,
+		},
+		{
+			name:           "Happy case: POST with an idempotency key is retried",
+			method:         http.MethodPost,
+			idempotencyKey: "create-facility-1",
+			policy:         fastRetryPolicy(),
+			responses:      []int{http.StatusServiceUnavailable, http.StatusCreated},
+			wantStatus:     http.StatusCreated,
+			wantCalls:      2,
+		},
+		{
+			name:           "Happy case: PATCH with an idempotency key is retried",
+			method:         http.MethodPatch,
+			idempotencyKey: "update-facility-1",
+			policy:         fastRetryPolicy(),
+			responses:      []int{http.StatusBadGateway, http.StatusOK},
+			wantStatus:     http.StatusOK,
+			wantCalls:      2,
+		},
+		{
+			name:       "Happy case: retries disabled",
+			method:     http.MethodGet,
+			policy:     NoRetryPolicy(),
+			responses:  []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantStatus: http.StatusServiceUnavailable,
+			wantCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			calls := 0
+			var bodies []string
+			var keys []string
+
+			path := "https://healthcrm.test/v1/facilities/facilities/"
+			httpmock.RegisterResponder(tt.method, path, func(req *http.Request) (*http.Response, error) {
+				if req.Body != nil {
+					body, _ := io.ReadAll(req.Body)
+					bodies = append(bodies, string(body))
+				}
+
+				keys = append(keys, req.Header.Get(idempotencyKeyHeader))
+
+				status := tt.responses[calls]
+				calls++
+
+				return httpmock.NewStringResponse(status, "{}"), nil
+			})
+
+			mockClient := newTestClient(t, &MockAuthUtilsLib{})
+			mockClient.retryPolicy = tt.policy
+
+			ctx := context.Background()
+			if tt.idempotencyKey != "" {
+				ctx = WithIdempotencyKey(ctx, tt.idempotencyKey)
+			}
+
+			var body interface{}
+			if tt.method != http.MethodGet {
+				body = &Facility{Name: "Test Facility"}
+			}
+
+			response, err := mockClient.MakeRequest(ctx, tt.method, "/v1/facilities/facilities/", nil, body)
+			if err != nil {
+				t.Fatalf("Error making request: %v", err)
+			}
+
+			defer response.Body.Close()
+
+			if response.StatusCode != tt.wantStatus {
+				t.Errorf("Expected status code %d, got %d", tt.wantStatus, response.StatusCode)
+			}
+
+			if calls != tt.wantCalls {
+				t.Errorf("Expected %d calls, got %d", tt.wantCalls, calls)
+			}
+
+			for _, got := range bodies {
+				if tt.method != http.MethodGet && got != `{"name":"Test Facility"}` {
+					t.Errorf("Expected the body to be resent on every attempt, got %q", got)
+				}
+			}
+
+			for _, got := range keys {
+				if got != tt.idempotencyKey {
+					t.Errorf("Expected idempotency key %q, got %q", tt.idempotencyKey, got)
+				}
+			}
+		})
+	}
+}
+
+func TestMakeRequest_RetryConnectionError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	calls := 0
+	path := "https://healthcrm.test/v1/facilities/facilities/"
+	httpmock.RegisterResponder(http.MethodGet, path, func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("connection reset by peer")
+		}
+
+		return httpmock.NewStringResponse(http.StatusOK, "{}"), nil
+	})
+
+	mockClient := newTestClient(t, &MockAuthUtilsLib{})
+	mockClient.retryPolicy = fastRetryPolicy()
+
+	response, err := mockClient.MakeRequest(context.Background(), http.MethodGet, "/v1/facilities/facilities/", nil, nil)
+	if err != nil {
+		t.Fatalf("Error making request: %v", err)
+	}
+
+	defer response.Body.Close()
+
+	if calls != 2 {
+		t.Errorf("Expected the request to be retried once, got %d calls", calls)
+	}
+}
+
+func TestMakeRequest_RetryRespectsDeadline(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	calls := 0
+	path := "https://healthcrm.test/v1/facilities/facilities/"
+	httpmock.RegisterResponder(http.MethodGet, path, func(req *http.Request) (*http.Response, error) {
+		calls++
+
+		response := httpmock.NewStringResponse(http.StatusServiceUnavailable, "{}")
+		response.Header.Set("Retry-After", "120")
+
+		return response, nil
+	})
+
+	mockClient := newTestClient(t, &MockAuthUtilsLib{})
+	mockClient.retryPolicy = fastRetryPolicy()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+
+	response, err := mockClient.MakeRequest(ctx, http.MethodGet, "/v1/facilities/facilities/", nil, nil)
+	if err != nil {
+		t.Fatalf("Error making request: %v", err)
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected status code %d, got %d", http.StatusServiceUnavailable, response.StatusCode)
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected no retry past the deadline, got %d calls", calls)
+	}
+
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("Expected to give up without waiting for Retry-After, took %v", time.Since(start))
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{
+			name:   "Happy case: seconds",
+			value:  "30",
+			want:   30 * time.Second,
+			wantOK: true,
+		},
+		{
+			name:   "Happy case: HTTP date",
+			value:  now.Add(time.Minute).Format(http.TimeFormat),
+			want:   time.Minute,
+			wantOK: true,
+		},
+		{
+			name:   "Happy case: HTTP date in the past",
+			value:  now.Add(-time.Minute).Format(http.TimeFormat),
+			want:   0,
+			wantOK: true,
+		},
+		{
+			name:   "Sad case: empty",
+			value:  "",
+			wantOK: false,
+		},
+		{
+			name:   "Sad case: negative seconds",
+			value:  "-1",
+			wantOK: false,
+		},
+		{
+			name:   "Sad case: invalid value",
+			value:  "soon",
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value, now)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("parseRetryAfter() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     300 * time.Millisecond,
+	}
+
+	tests := []struct {
+		name    string
+		retry   int
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{
+			name:    "first retry",
+			retry:   1,
+			wantMin: 50 * time.Millisecond,
+			wantMax: 100 * time.Millisecond,
+		},
+		{
+			name:    "second retry doubles",
+			retry:   2,
+			wantMin: 100 * time.Millisecond,
+			wantMax: 200 * time.Millisecond,
+		},
+		{
+			name:    "capped at the maximum backoff",
+			retry:   5,
+			wantMin: 150 * time.Millisecond,
+			wantMax: 300 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.backoff(tt.retry, nil)
+			if got < tt.wantMin || got > tt.wantMax {
+				t.Errorf("RetryPolicy.backoff() = %v, want between %v and %v", got, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		wantErr bool
+	}{
+		{
+			name:    "Happy case: default policy",
+			policy:  DefaultRetryPolicy(),
+			wantErr: false,
+		},
+		{
+			name:    "Sad case: negative backoff",
+			policy:  RetryPolicy{MaxAttempts: 3, InitialBackoff: -time.Second},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: maximum backoff less than initial backoff",
+			policy:  RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: time.Millisecond},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := WithRetryPolicy(tt.policy)(defaultOptions())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WithRetryPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
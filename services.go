@@ -0,0 +1,161 @@
+package healthcrm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// UpdateServiceInput is used to change a service's name or description. Empty fields are left as they are.
+type UpdateServiceInput struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Validate checks that there is something to update
+func (u UpdateServiceInput) Validate() error {
+	if u.Name == "" && u.Description == "" {
+		return errors.New("service name or description must be provided")
+	}
+
+	return nil
+}
+
+// Validate checks that the identifier type is known and that the value is provided
+func (s ServiceIdentifierInput) Validate() error {
+	if !s.IdentifierType.IsValid() {
+		return fmt.Errorf("invalid service identifier type: %s", s.IdentifierType)
+	}
+
+	if s.IdentifierValue == "" {
+		return errors.New("identifier value must be provided")
+	}
+
+	return nil
+}
+
+// servicePath returns the path of a service
+func servicePath(serviceID string) string {
+	return fmt.Sprintf("/v1/facilities/services/%s/", serviceID)
+}
+
+// serviceIdentifiersPath returns the path of a service's identifiers
+func serviceIdentifiersPath(serviceID string) string {
+	return fmt.Sprintf("/v1/facilities/services/%s/identifiers/", serviceID)
+}
+
+// UpdateService changes the name or description of a service in the catalogue
+func (h *HealthCRMLib) UpdateService(ctx context.Context, serviceID string, input UpdateServiceInput) (*FacilityService, error) {
+	if serviceID == "" {
+		return nil, errors.New("service ID must be provided")
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	response, err := h.client.MakeRequest(ctx, http.MethodPatch, servicePath(serviceID), nil, input)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	respBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read response: %w", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, newResponseError(response, respBytes)
+	}
+
+	var output *FacilityService
+
+	err = json.Unmarshal(respBytes, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// DeleteService removes a service from the catalogue
+func (h *HealthCRMLib) DeleteService(ctx context.Context, serviceID string) error {
+	if serviceID == "" {
+		return errors.New("service ID must be provided")
+	}
+
+	return h.deleteServiceResource(ctx, servicePath(serviceID))
+}
+
+// AddServiceIdentifier adds a code e.g a CIEL concept to a service
+func (h *HealthCRMLib) AddServiceIdentifier(ctx context.Context, serviceID string, input ServiceIdentifierInput) (*ServiceIdentifier, error) {
+	if serviceID == "" {
+		return nil, errors.New("service ID must be provided")
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	response, err := h.client.MakeRequest(ctx, http.MethodPost, serviceIdentifiersPath(serviceID), nil, input)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	respBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read response: %w", err)
+	}
+
+	if response.StatusCode != http.StatusCreated {
+		return nil, newResponseError(response, respBytes)
+	}
+
+	var output *ServiceIdentifier
+
+	err = json.Unmarshal(respBytes, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// RemoveServiceIdentifier removes one of the identifiers in a service's Identifiers
+func (h *HealthCRMLib) RemoveServiceIdentifier(ctx context.Context, identifier ServiceIdentifier) error {
+	if identifier.ServiceID == "" || identifier.ID == "" {
+		return errors.New("service ID and identifier ID must be provided")
+	}
+
+	path := fmt.Sprintf("%s%s/", serviceIdentifiersPath(identifier.ServiceID), identifier.ID)
+
+	return h.deleteServiceResource(ctx, path)
+}
+
+// deleteServiceResource deletes a service or one of its identifiers
+func (h *HealthCRMLib) deleteServiceResource(ctx context.Context, path string) error {
+	response, err := h.client.MakeRequest(ctx, http.MethodDelete, path, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	respBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("could not read response: %w", err)
+	}
+
+	if response.StatusCode != http.StatusNoContent && response.StatusCode != http.StatusOK {
+		return newResponseError(response, respBytes)
+	}
+
+	return nil
+}
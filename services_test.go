@@ -0,0 +1,220 @@
+package healthcrm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestServiceIdentifierInput_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   ServiceIdentifierInput
+		wantErr bool
+	}{
+		{
+			name:  "Happy case: CIEL concept",
+			input: ServiceIdentifierInput{IdentifierType: ServiceIdentifierTypeCIEL, IdentifierValue: "1234"},
+		},
+		{
+			name:    "Sad case: invalid type",
+			input:   ServiceIdentifierInput{IdentifierType: ServiceIdentifierType("ICD"), IdentifierValue: "1234"},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case: missing value",
+			input:   ServiceIdentifierInput{IdentifierType: ServiceIdentifierTypeLOINC},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.input.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("ServiceIdentifierInput.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHealthCRMLib_ServiceLifecycle(t *testing.T) {
+	ctx := context.Background()
+	identifier := ServiceIdentifier{ID: "789", IdentifierType: "CIEL", IdentifierValue: "1234", ServiceID: "456"}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		statusCode int
+		call       func(h *HealthCRMLib) error
+		wantBody   map[string]any
+		wantErr    error
+	}{
+		{
+			name:       "Happy case: update service",
+			method:     http.MethodPatch,
+			path:       "/v1/facilities/services/456/",
+			statusCode: http.StatusOK,
+			call: func(h *HealthCRMLib) error {
+				service, err := h.UpdateService(ctx, "456", UpdateServiceInput{Name: "Antenatal care"})
+				if err == nil && service.ID != "456" {
+					return fmt.Errorf("unexpected service %+v", service)
+				}
+				return err
+			},
+			wantBody: map[string]any{"name": "Antenatal care"},
+		},
+		{
+			name:       "Happy case: delete service",
+			method:     http.MethodDelete,
+			path:       "/v1/facilities/services/456/",
+			statusCode: http.StatusNoContent,
+			call: func(h *HealthCRMLib) error {
+				return h.DeleteService(ctx, "456")
+			},
+		},
+		{
+			name:       "Happy case: add service identifier",
+			method:     http.MethodPost,
+			path:       "/v1/facilities/services/456/identifiers/",
+			statusCode: http.StatusCreated,
+			call: func(h *HealthCRMLib) error {
+				added, err := h.AddServiceIdentifier(ctx, "456", ServiceIdentifierInput{IdentifierType: ServiceIdentifierTypeSNOMEDCT, IdentifierValue: "424525001"})
+				if err == nil && added.ID != "789" {
+					return fmt.Errorf("unexpected identifier %+v", added)
+				}
+				return err
+			},
+			wantBody: map[string]any{"identifier_type": "SNOMED_CT", "identifier_value": "424525001"},
+		},
+		{
+			name:       "Happy case: remove service identifier",
+			method:     http.MethodDelete,
+			path:       "/v1/facilities/services/456/identifiers/789/",
+			statusCode: http.StatusNoContent,
+			call: func(h *HealthCRMLib) error {
+				return h.RemoveServiceIdentifier(ctx, identifier)
+			},
+		},
+		{
+			name:       "Sad case: delete missing service",
+			method:     http.MethodDelete,
+			path:       "/v1/facilities/services/456/",
+			statusCode: http.StatusNotFound,
+			call: func(h *HealthCRMLib) error {
+				return h.DeleteService(ctx, "456")
+			},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:       "Sad case: duplicate service identifier",
+			method:     http.MethodPost,
+			path:       "/v1/facilities/services/456/identifiers/",
+			statusCode: http.StatusBadRequest,
+			call: func(h *HealthCRMLib) error {
+				_, err := h.AddServiceIdentifier(ctx, "456", ServiceIdentifierInput{IdentifierType: ServiceIdentifierTypeCIEL, IdentifierValue: "1234"})
+				return err
+			},
+			wantErr: ErrValidation,
+		},
+		{
+			name:   "Sad case: invalid identifier type is not sent",
+			method: http.MethodPost,
+			path:   "/v1/facilities/services/456/identifiers/",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.AddServiceIdentifier(ctx, "456", ServiceIdentifierInput{IdentifierType: "ICD", IdentifierValue: "1234"})
+				return err
+			},
+		},
+		{
+			name:   "Sad case: empty update is not sent",
+			method: http.MethodPatch,
+			path:   "/v1/facilities/services/456/",
+			call: func(h *HealthCRMLib) error {
+				_, err := h.UpdateService(ctx, "456", UpdateServiceInput{})
+				return err
+			},
+		},
+		{
+			name:   "Sad case: identifier without a service is not sent",
+			method: http.MethodDelete,
+			path:   "/v1/facilities/services//identifiers/789/",
+			call: func(h *HealthCRMLib) error {
+				return h.RemoveServiceIdentifier(ctx, ServiceIdentifier{ID: "789"})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			MockAuthenticate()
+
+			var sent map[string]any
+
+			url := fmt.Sprintf("%s%s", baseURL, tt.path)
+			httpmock.RegisterResponder(tt.method, url, func(r *http.Request) (*http.Response, error) {
+				if r.Body != nil && r.Body != http.NoBody {
+					if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+						return nil, err
+					}
+				}
+
+				switch tt.statusCode {
+				case http.StatusNotFound:
+					return httpmock.NewStringResponse(tt.statusCode, `{"detail": "Not found."}`), nil
+				case http.StatusBadRequest:
+					return httpmock.NewStringResponse(tt.statusCode, `{"identifier_value": ["This identifier already exists."]}`), nil
+				case http.StatusNoContent:
+					return httpmock.NewStringResponse(tt.statusCode, ""), nil
+				case http.StatusCreated:
+					return httpmock.NewJsonResponse(tt.statusCode, &identifier)
+				default:
+					return httpmock.NewJsonResponse(tt.statusCode, &FacilityService{ID: "456", Name: "Antenatal care"})
+				}
+			})
+
+			h, err := NewHealthCRMLib(testOptions(baseURL, WithRetryPolicy(NoRetryPolicy()))...)
+			if err != nil {
+				t.Fatalf("unable to initialize sdk: %v", err)
+			}
+			defer h.Close()
+
+			err = tt.call(h)
+
+			if tt.statusCode == 0 {
+				if err == nil {
+					t.Errorf("expected an error for invalid input")
+				}
+
+				if count := httpmock.GetCallCountInfo()[tt.method+" "+url]; count != 0 {
+					t.Errorf("invalid input sent %d requests, want none", count)
+				}
+
+				return
+			}
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for key, value := range tt.wantBody {
+				if sent[key] != value {
+					t.Errorf("request body %s = %v, want %v", key, sent[key], value)
+				}
+			}
+		})
+	}
+}
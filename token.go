@@ -0,0 +1,120 @@
+package healthcrm
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/savannahghi/authutils"
+	"github.com/sirupsen/logrus"
+)
+
+// Token is an access token used to authorize requests to health CRM
+type Token struct {
+	AccessToken string
+	// Expiry is the time at which the access token expires. The zero value means the expiry is unknown.
+	Expiry time.Time
+}
+
+// TokenSource supplies the access tokens used to authorize requests to health CRM.
+//
+// Token is called when the library is initialized, shortly before the current token expires
+// and whenever health CRM rejects the current token with 401 Unauthorized.
+// It should return a new token each time it is called if the underlying mechanism supports it.
+// The library never calls Token concurrently.
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// IAuthUtilsLib holds the method defined in authutils library
+type authUtilsLib interface {
+	Authenticate() (*authutils.OAUTHResponse, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*authutils.OAUTHResponse, error)
+}
+
+// authUtilsTokenSource obtains tokens from a slade360 auth server using the authutils library.
+// It refreshes the previous token when it can and logs in again when the refresh token is rejected.
+type authUtilsTokenSource struct {
+	authClient   authUtilsLib
+	logger       logrus.FieldLogger
+	mu           sync.Mutex
+	refreshToken string
+}
+
+// NewAuthUtilsTokenSource creates the default TokenSource which logs in to a slade360 auth server
+// with the credentials and grant type in config
+func NewAuthUtilsTokenSource(config authutils.Config) (TokenSource, error) {
+	authClient, err := authutils.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &authUtilsTokenSource{
+		authClient: authClient,
+		logger:     logrus.StandardLogger(),
+	}, nil
+}
+
+// Token refreshes the previous token, falling back to a full login if there is none or the refresh fails
+func (s *authUtilsTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.refreshToken != "" {
+		token, err := s.authClient.RefreshToken(ctx, s.refreshToken)
+		if err == nil {
+			return s.setToken(token), nil
+		}
+
+		s.logger.Printf("HealthCRM token refresh failed, logging in again: %v", err)
+	}
+
+	token, err := s.authClient.Authenticate()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.setToken(token), nil
+}
+
+// setToken keeps the refresh token for the next call and converts the auth server's response
+func (s *authUtilsTokenSource) setToken(response *authutils.OAUTHResponse) *Token {
+	s.refreshToken = response.RefreshToken
+
+	token := &Token{
+		AccessToken: response.AccessToken,
+	}
+
+	if response.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(response.ExpiresIn) * time.Second)
+	}
+
+	return token
+}
+
+// staticTokenSource always returns the same token
+type staticTokenSource struct {
+	token Token
+}
+
+// StaticTokenSource returns a TokenSource that always returns the same access token.
+// It is meant for tests and for tokens whose lifetime is managed outside the library.
+func StaticTokenSource(accessToken string) TokenSource {
+	return &staticTokenSource{
+		token: Token{
+			AccessToken: accessToken,
+		},
+	}
+}
+
+// Token returns the static token
+func (s *staticTokenSource) Token(ctx context.Context) (*Token, error) {
+	if s.token.AccessToken == "" {
+		return nil, errors.New("static access token must not be empty")
+	}
+
+	token := s.token
+
+	return &token, nil
+}
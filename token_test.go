@@ -0,0 +1,204 @@
+package healthcrm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/savannahghi/authutils"
+	"github.com/sirupsen/logrus"
+)
+
+func TestAuthUtilsTokenSource_Token(t *testing.T) {
+	tests := []struct {
+		name          string
+		rejectRefresh bool
+		wantTokens    []string
+		wantLogins    int64
+		wantRefreshes int64
+	}{
+		{
+			name:          "Happy case: login then refresh",
+			rejectRefresh: false,
+			wantTokens:    []string{"login-1", "refreshed-1", "refreshed-2"},
+			wantLogins:    1,
+			wantRefreshes: 2,
+		},
+		{
+			name:          "Happy case: refresh token rejected, login again",
+			rejectRefresh: true,
+			wantTokens:    []string{"login-1", "login-2", "login-3"},
+			wantLogins:    3,
+			wantRefreshes: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authClient := &countingAuthUtilsLib{rejectRefresh: tt.rejectRefresh}
+			source := &authUtilsTokenSource{
+				authClient: authClient,
+				logger:     logrus.StandardLogger(),
+			}
+
+			for _, want := range tt.wantTokens {
+				token, err := source.Token(context.Background())
+				if err != nil {
+					t.Fatalf("authUtilsTokenSource.Token() error = %v", err)
+				}
+
+				if token.AccessToken != want {
+					t.Errorf("authUtilsTokenSource.Token() = %q, want %q", token.AccessToken, want)
+				}
+			}
+
+			if got := authClient.logins.Load(); got != tt.wantLogins {
+				t.Errorf("Expected %d logins, got %d", tt.wantLogins, got)
+			}
+
+			if got := authClient.refreshes.Load(); got != tt.wantRefreshes {
+				t.Errorf("Expected %d refreshes, got %d", tt.wantRefreshes, got)
+			}
+		})
+	}
+}
+
+// expiringAuthUtilsLib issues tokens that expire after expiresIn seconds
+type expiringAuthUtilsLib struct {
+	expiresIn int
+}
+
+// Authenticate mocks implementation of authutil's library
+func (m *expiringAuthUtilsLib) Authenticate() (*authutils.OAUTHResponse, error) {
+	return &authutils.OAUTHResponse{
+		AccessToken: "access",
+		ExpiresIn:   m.expiresIn,
+	}, nil
+}
+
+// RefreshToken mocks implementation of authutil's library
+func (m *expiringAuthUtilsLib) RefreshToken(ctx context.Context, refreshToken string) (*authutils.OAUTHResponse, error) {
+	return m.Authenticate()
+}
+
+func TestAuthUtilsTokenSource_Expiry(t *testing.T) {
+	source := &authUtilsTokenSource{
+		authClient: &expiringAuthUtilsLib{expiresIn: 600},
+		logger:     logrus.StandardLogger(),
+	}
+
+	before := time.Now()
+
+	token, err := source.Token(context.Background())
+	if err != nil {
+		t.Fatalf("authUtilsTokenSource.Token() error = %v", err)
+	}
+
+	if token.Expiry.Before(before.Add(10*time.Minute)) || token.Expiry.After(time.Now().Add(10*time.Minute)) {
+		t.Errorf("expected the token to expire in ten minutes, got %v", token.Expiry)
+	}
+
+	source.authClient = &expiringAuthUtilsLib{}
+
+	token, err = source.Token(context.Background())
+	if err != nil {
+		t.Fatalf("authUtilsTokenSource.Token() error = %v", err)
+	}
+
+	if !token.Expiry.IsZero() {
+		t.Errorf("expected a zero expiry when expires_in is not provided, got %v", token.Expiry)
+	}
+}
+
+func TestStaticTokenSource(t *testing.T) {
+	tests := []struct {
+		name        string
+		accessToken string
+		wantErr     bool
+	}{
+		{
+			name:        "Happy case: static token",
+			accessToken: "static-token",
+			wantErr:     false,
+		},
+		{
+			name:        "Sad case: empty token",
+			accessToken: "",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := StaticTokenSource(tt.accessToken).Token(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("StaticTokenSource().Token() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && token.AccessToken != tt.accessToken {
+				t.Errorf("StaticTokenSource().Token() = %q, want %q", token.AccessToken, tt.accessToken)
+			}
+		})
+	}
+}
+
+func TestNewHealthCRMLib_WithTokenSource(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var authorization string
+
+	path := "https://staging.healthcrm.test/v1/facilities/facilities/123/"
+	httpmock.RegisterResponder(http.MethodGet, path, func(r *http.Request) (*http.Response, error) {
+		authorization = r.Header.Get("Authorization")
+		return httpmock.NewJsonResponse(http.StatusOK, FacilityOutput{ID: "123"})
+	})
+
+	h, err := NewHealthCRMLib(WithBaseURL("https://staging.healthcrm.test"), WithTokenSource(StaticTokenSource("static-token")))
+	if err != nil {
+		t.Fatalf("unable to initialize sdk: %v", err)
+	}
+	defer h.Close()
+
+	_, err = h.GetFacilityByID(context.Background(), "123")
+	if err != nil {
+		t.Fatalf("GetFacilityByID() error = %v", err)
+	}
+
+	if authorization != fmt.Sprintf("Bearer %s", "static-token") {
+		t.Errorf("expected the static token to be sent, got %q", authorization)
+	}
+
+	_, err = NewHealthCRMLib(WithBaseURL("https://staging.healthcrm.test"), WithTokenSource(nil))
+	if err == nil {
+		t.Errorf("NewHealthCRMLib() expected an error for a nil token source")
+	}
+}
+
+func TestNewAuthUtilsTokenSource(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	mockTestAuthServer()
+
+	source, err := NewAuthUtilsTokenSource(authutils.Config{
+		AuthServerEndpoint: testAuthServerEndpoint,
+		ClientID:           "client-id",
+		ClientSecret:       "client-secret",
+		GrantType:          "password",
+		Username:           "user@example.com",
+		Password:           "password",
+	})
+	if err != nil {
+		t.Fatalf("NewAuthUtilsTokenSource() error = %v", err)
+	}
+
+	token, err := source.Token(context.Background())
+	if err != nil {
+		t.Fatalf("authUtilsTokenSource.Token() error = %v", err)
+	}
+
+	if token.AccessToken != "testAccessToken" {
+		t.Errorf("authUtilsTokenSource.Token() = %q, want %q", token.AccessToken, "testAccessToken")
+	}
+}